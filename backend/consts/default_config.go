@@ -8,3 +8,20 @@ const MIN_WINDOW_WIDTH = 960
 const MIN_WINDOW_HEIGHT = 640
 const DEFAULT_LOAD_SIZE = 10000
 const DEFAULT_SCAN_SIZE = 3000
+const MAX_SCAN_SIZE = 50000 // max COUNT of SCAN when auto-tuning
+const DEFAULT_SUB_BATCH_SIZE = 300
+const DEFAULT_SUB_FLUSH_INTERVAL = 300 // milliseconds
+const DEFAULT_SUB_HISTORY_SIZE = 1000
+const DEFAULT_SUB_CHANNELS_REFRESH = 5000 // milliseconds
+const DEFAULT_CLI_HISTORY_SIZE = 1000
+const DEFAULT_CLI_LATENCY_SAMPLES = 1000   // recent durations kept for percentile of each command
+const MAX_CLI_LATENCY_COMMANDS = 500       // distinct command names tracked of each server
+const DEFAULT_SERVER_STATS_INTERVAL = 1000 // milliseconds
+const MAX_BITMAP_PAGE_SIZE = 4096          // bytes
+const MAX_POOL_SIZE = 1000
+const DEFAULT_MEMORY_SAMPLE_SIZE = 10000
+const DEFAULT_WAIT_TIMEOUT = 1000 // milliseconds
+const DEFAULT_SLOT_SAMPLE_SIZE = 10000
+const DEFAULT_AUDIT_LOG_LIMIT = 100
+const CERT_EXPIRY_WARNING_DAYS = 30
+const DEFAULT_SHUTDOWN_TIMEOUT = 3000 // milliseconds
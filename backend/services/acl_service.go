@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"tinyrdm/backend/types"
+	sliceutil "tinyrdm/backend/utils/slice"
+	strutil "tinyrdm/backend/utils/string"
+)
+
+type aclSelector struct {
+	Commands []string `json:"commands"`
+	Keys     []string `json:"keys"`
+	Channels []string `json:"channels"`
+}
+
+type aclUser struct {
+	Name      string        `json:"name"`
+	Enabled   bool          `json:"enabled"`
+	Flags     []string      `json:"flags"`
+	Commands  []string      `json:"commands"` // command rules like "+@all", "-debug"
+	Keys      []string      `json:"keys"`     // key patterns like "~*", "%R~cache:*"
+	Channels  []string      `json:"channels"` // channel patterns like "&*"
+	Selectors []aclSelector `json:"selectors,omitempty"`
+	Rule      string        `json:"rule"` // full rule from ACL LIST
+}
+
+type aclService struct {
+	ctx context.Context
+}
+
+var acl *aclService
+var onceAcl sync.Once
+
+func Acl() *aclService {
+	if acl == nil {
+		onceAcl.Do(func() {
+			acl = &aclService{}
+		})
+	}
+	return acl
+}
+
+func (a *aclService) Start(ctx context.Context) {
+	a.ctx = ctx
+}
+
+// wrap error of unsupported command to readable capability error
+func (a *aclService) wrapError(err error, subCmd, version string) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "unknown command") || strings.Contains(msg, "unknown subcommand") {
+		return fmt.Errorf("ACL %s is not supported, requires Redis %s or later", subCmd, version)
+	}
+	return err
+}
+
+// convert reply of RESP2 array with alternate key and value or RESP3 map to map
+func (a *aclService) toReplyMap(reply any) map[string]any {
+	result := map[string]any{}
+	switch r := reply.(type) {
+	case map[any]any:
+		for k, v := range r {
+			result[strutil.AnyToString(k, "", 0)] = v
+		}
+	case map[string]any:
+		return r
+	case []any:
+		for i := 0; i+1 < len(r); i += 2 {
+			result[strutil.AnyToString(r[i], "", 0)] = r[i+1]
+		}
+	}
+	return result
+}
+
+// convert rules to list, which may be an array(Redis 6) or a space separated string(Redis 7+)
+func (a *aclService) toRuleList(reply any) []string {
+	switch r := reply.(type) {
+	case []any:
+		return sliceutil.Map(r, func(i int) string {
+			return strutil.AnyToString(r[i], "", 0)
+		})
+	case string:
+		return strings.Fields(r)
+	}
+	return []string{}
+}
+
+// parse reply of ACL GETUSER
+func (a *aclService) parseUser(name string, reply any) aclUser {
+	info := a.toReplyMap(reply)
+	user := aclUser{
+		Name:     name,
+		Flags:    a.toRuleList(info["flags"]),
+		Commands: a.toRuleList(info["commands"]),
+		Keys:     a.toRuleList(info["keys"]),
+		Channels: a.toRuleList(info["channels"]),
+	}
+	user.Enabled = slices.Contains(user.Flags, "on")
+	if selectors, ok := info["selectors"].([]any); ok {
+		for _, sel := range selectors {
+			selInfo := a.toReplyMap(sel)
+			user.Selectors = append(user.Selectors, aclSelector{
+				Commands: a.toRuleList(selInfo["commands"]),
+				Keys:     a.toRuleList(selInfo["keys"]),
+				Channels: a.toRuleList(selInfo["channels"]),
+			})
+		}
+	}
+	return user
+}
+
+// GetUsers get all acl users with parsed rules
+func (a *aclService) GetUsers(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	rules, err := client.Do(ctx, "ACL", "LIST").StringSlice()
+	if err != nil {
+		resp.Msg = a.wrapError(err, "LIST", "6.0").Error()
+		return
+	}
+
+	users := make([]aclUser, 0, len(rules))
+	for _, rule := range rules {
+		// user <name> <rules...>
+		fields := strings.Fields(rule)
+		if len(fields) < 2 {
+			continue
+		}
+		reply, err := client.Do(ctx, "ACL", "GETUSER", fields[1]).Result()
+		if err != nil {
+			resp.Msg = a.wrapError(err, "GETUSER", "6.0").Error()
+			return
+		}
+		user := a.parseUser(fields[1], reply)
+		user.Rule = rule
+		users = append(users, user)
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Users []aclUser `json:"users"`
+	}{
+		Users: users,
+	}
+	return
+}
+
+// WhoAmI get username of current connection
+func (a *aclService) WhoAmI(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	username, err := client.Do(ctx, "ACL", "WHOAMI").Text()
+	if err != nil {
+		resp.Msg = a.wrapError(err, "WHOAMI", "6.0").Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Username string `json:"username"`
+	}{
+		Username: username,
+	}
+	return
+}
+
+// TestCommand check if user has permission to execute command by ACL DRYRUN, without really executing it
+// @param username check current user if empty
+// @param commandLine command with arguments
+func (a *aclService) TestCommand(server, username, commandLine string) (resp types.JSResp) {
+	cmds := strutil.SplitCmd(commandLine)
+	if len(cmds) <= 0 || len(cmds[0]) <= 0 {
+		resp.Msg = "empty command"
+		return
+	}
+
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if len(username) <= 0 {
+		if username, err = client.Do(ctx, "ACL", "WHOAMI").Text(); err != nil {
+			resp.Msg = a.wrapError(err, "WHOAMI", "6.0").Error()
+			return
+		}
+	}
+
+	args := []any{"ACL", "DRYRUN", username}
+	for _, c := range cmds {
+		args = append(args, c)
+	}
+	// reply "OK" if permitted, otherwise reply the reason
+	result, err := client.Do(ctx, args...).Text()
+	if err != nil {
+		if msg := err.Error(); strings.HasPrefix(msg, "ERR User ") && strings.Contains(msg, "has no permissions") {
+			// denied reason may also be replied as error, others like NOPERM of ACL itself are failure of testing
+			result = strings.TrimPrefix(msg, "ERR ")
+			err = nil
+		} else {
+			err = a.wrapError(err, "DRYRUN", "7.0")
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	allowed := strings.EqualFold(result, "OK")
+	var reason string
+	if !allowed {
+		reason = result
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Username string `json:"username"`
+		Allowed  bool   `json:"allowed"`
+		Reason   string `json:"reason,omitempty"`
+	}{
+		Username: username,
+		Allowed:  allowed,
+		Reason:   reason,
+	}
+	return
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"tinyrdm/backend/consts"
+	storage2 "tinyrdm/backend/storage"
+	"tinyrdm/backend/types"
+	redis2 "tinyrdm/backend/utils/redis"
+	sliceutil "tinyrdm/backend/utils/slice"
+)
+
+type auditService struct {
+	ctx  context.Context
+	logs *storage2.AuditLogStorage
+}
+
+var audit *auditService
+var onceAudit sync.Once
+
+func Audit() *auditService {
+	if audit == nil {
+		onceAudit.Do(func() {
+			audit = &auditService{
+				logs: storage2.NewAuditLog(),
+			}
+		})
+	}
+	return audit
+}
+
+func (a *auditService) Start(ctx context.Context) {
+	a.ctx = ctx
+}
+
+// record append result of mutating operation to audit log if enabled by connection profile
+// resp is the response of operation, read after operation finished
+func (a *auditService) record(server string, db int, operation, key, detail string, resp *types.JSResp) {
+	a.recordKeys(server, db, operation, []string{key}, detail, resp)
+}
+
+// recordKeys the same as record, but append one entry for each key
+func (a *auditService) recordKeys(server string, db int, operation string, keys []string, detail string, resp *types.JSResp) {
+	if conn := Connection().getConnection(server); conn == nil || !conn.AuditLog {
+		return
+	}
+	now := time.Now().UnixMilli()
+	entries := make([]types.AuditEntry, len(keys))
+	for i, key := range keys {
+		entries[i] = types.AuditEntry{
+			Time:      now,
+			Server:    server,
+			DB:        db,
+			Key:       key,
+			Operation: operation,
+			Detail:    detail,
+			Success:   resp.Success,
+			Error:     resp.Msg,
+		}
+	}
+	if err := a.logs.Append(entries...); err != nil {
+		log.Printf("write audit log fail: %s\n", err.Error())
+	}
+}
+
+// recordCommands append one entry for each write command executed by cli, read-only commands are skipped
+// @param errs error of each command, empty if succeeded
+func (a *auditService) recordCommands(server string, db int, commands [][]string, errs []string) {
+	if conn := Connection().getConnection(server); conn == nil || !conn.AuditLog {
+		return
+	}
+	now := time.Now().UnixMilli()
+	entries := make([]types.AuditEntry, 0, len(commands))
+	for i, cmds := range commands {
+		args := sliceutil.Map(cmds, func(i int) any {
+			return cmds[i]
+		})
+		if !redis2.IsWriteCommand(args) {
+			continue
+		}
+		var errMsg string
+		if i < len(errs) {
+			errMsg = errs[i]
+		}
+		entries = append(entries, types.AuditEntry{
+			Time:      now,
+			Server:    server,
+			DB:        db,
+			Operation: "command",
+			Detail:    strings.Join(maskSecretArgs(cmds), " "),
+			Success:   len(errMsg) <= 0,
+			Error:     errMsg,
+		})
+	}
+	if len(entries) <= 0 {
+		return
+	}
+	if err := a.logs.Append(entries...); err != nil {
+		log.Printf("write audit log fail: %s\n", err.Error())
+	}
+}
+
+// config parameters which contain password
+var secretConfigParams = map[string]struct{}{
+	"requirepass":              {},
+	"masterauth":               {},
+	"tls-key-file-pass":        {},
+	"tls-client-key-file-pass": {},
+}
+
+const maskedSecret = "******"
+
+// maskSecretArgs replace passwords in command arguments before written to audit log
+// covers CONFIG SET, ACL SETUSER and AUTH/AUTH2 option of MIGRATE
+func maskSecretArgs(cmds []string) []string {
+	if len(cmds) <= 0 {
+		return cmds
+	}
+	masked := slices.Clone(cmds)
+	name := strings.ToLower(cmds[0])
+	var sub string
+	if len(cmds) > 1 {
+		sub = strings.ToLower(cmds[1])
+	}
+	switch {
+	case name == "config" && sub == "set":
+		// CONFIG SET parameter value [parameter value ...]
+		for i := 2; i+1 < len(masked); i += 2 {
+			if _, ok := secretConfigParams[strings.ToLower(strings.TrimSpace(masked[i]))]; ok {
+				masked[i+1] = maskedSecret
+			}
+		}
+	case name == "acl" && sub == "setuser":
+		// ACL SETUSER username [rule ...], >password <password #hash !hash
+		for i := 3; i < len(masked); i++ {
+			if rule := masked[i]; len(rule) > 0 && strings.ContainsRune("><#!", rune(rule[0])) {
+				masked[i] = rule[:1] + maskedSecret
+			}
+		}
+	case name == "migrate":
+		// MIGRATE host port key db timeout [COPY] [REPLACE] [AUTH password | AUTH2 username password] [KEYS key ...]
+		for i := 6; i < len(masked); i++ {
+			switch strings.ToLower(masked[i]) {
+			case "auth":
+				if i+1 < len(masked) {
+					masked[i+1] = maskedSecret
+				}
+			case "auth2":
+				if i+2 < len(masked) {
+					masked[i+2] = maskedSecret
+				}
+			case "keys":
+				return masked
+			}
+		}
+	}
+	return masked
+}
+
+// GetAuditLogs get recent audit entries, the latest first
+// @param server filter by server name, empty for all servers
+// @param limit max count of entries
+func (a *auditService) GetAuditLogs(server string, limit int) (resp types.JSResp) {
+	if limit <= 0 {
+		limit = consts.DEFAULT_AUDIT_LOG_LIMIT
+	}
+	entries, err := a.logs.GetRecent(server, limit)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Entries []types.AuditEntry `json:"entries"`
+	}{
+		Entries: entries,
+	}
+	return
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
@@ -9,9 +11,11 @@ import (
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"io"
 	"math"
 	"net/url"
 	"os"
+	"reflect"
 	"slices"
 	"sort"
 	"strconv"
@@ -27,14 +31,51 @@ import (
 	redis2 "tinyrdm/backend/utils/redis"
 	sliceutil "tinyrdm/backend/utils/slice"
 	strutil "tinyrdm/backend/utils/string"
+	"unicode/utf8"
 )
 
 type slowLogItem struct {
-	Timestamp int64  `json:"timestamp"`
-	Client    string `json:"client"`
-	Addr      string `json:"addr"`
-	Cmd       string `json:"cmd"`
-	Cost      int64  `json:"cost"`
+	ID        int64    `json:"id"`
+	Timestamp int64    `json:"timestamp"`
+	Client    string   `json:"client"`
+	Addr      string   `json:"addr"`
+	Cmd       string   `json:"cmd"`
+	Args      []string `json:"args"`
+	Cost      int64    `json:"cost"`     // milliseconds
+	Duration  int64    `json:"duration"` // microseconds
+}
+
+type scanKeyItem struct {
+	Key  any    `json:"key"`
+	Type string `json:"type"`
+}
+
+type keyMemoryItem struct {
+	Key  any   `json:"key"`
+	Size int64 `json:"size"`
+}
+
+type hotKeyItem struct {
+	Key   any   `json:"key"`
+	Score int64 `json:"score"` // access frequency counter, or idle seconds
+}
+
+type bigKeyItem struct {
+	Key  any    `json:"key"`
+	Type string `json:"type"`
+	Size int64  `json:"size"` // length of string, count of elements, or memory usage of other types
+}
+
+type clientInfoItem struct {
+	ID     int64             `json:"id"`
+	Addr   string            `json:"addr"`
+	Name   string            `json:"name"`
+	Age    int64             `json:"age"`  // seconds
+	Idle   int64             `json:"idle"` // seconds
+	Flags  string            `json:"flags"`
+	DB     int               `json:"db"`
+	Cmd    string            `json:"cmd"`
+	Fields map[string]string `json:"fields"` // other fields not listed above
 }
 
 type entryCursor struct {
@@ -56,10 +97,17 @@ type connectionItem struct {
 	db          int // current database index
 }
 
+type ttlWatchItem struct {
+	server  string
+	closeCh chan struct{}
+}
+
 type browserService struct {
 	ctx        context.Context
 	connMap    map[string]*connectionItem
 	cmdHistory []cmdHistoryItem
+	deleting   map[string]context.CancelFunc // cancel functions of deleting by pattern
+	ttlWatches map[string]*ttlWatchItem      // ttl pollers of watching keys
 	mutex      sync.Mutex
 }
 
@@ -70,7 +118,9 @@ func Browser() *browserService {
 	if browser == nil {
 		onceBrowser.Do(func() {
 			browser = &browserService{
-				connMap: map[string]*connectionItem{},
+				connMap:    map[string]*connectionItem{},
+				deleting:   map[string]context.CancelFunc{},
+				ttlWatches: map[string]*ttlWatchItem{},
 			}
 		})
 	}
@@ -82,12 +132,12 @@ func (b *browserService) Start(ctx context.Context) {
 }
 
 func (b *browserService) Stop() {
-	for _, item := range b.connMap {
+	for server, item := range b.connMap {
 		if item.client != nil {
 			if item.cancelFunc != nil {
 				item.cancelFunc()
 			}
-			item.client.Close()
+			Connection().releaseClient(server, item.client)
 		}
 	}
 	b.connMap = map[string]*connectionItem{}
@@ -104,6 +154,10 @@ func (b *browserService) OpenConnection(name string) (resp types.JSResp) {
 	} else if selConn.DBFilterType == "hide" && slices.Contains(selConn.DBFilterList, lastDB) {
 		lastDB = selConn.DBFilterList[0]
 	}
+	if selConn.Cluster.Enable {
+		// only database 0 is available in cluster mode
+		lastDB = 0
+	}
 	if lastDB != selConn.LastDB {
 		Connection().SaveLastDB(name, lastDB)
 	}
@@ -244,21 +298,23 @@ func (b *browserService) OpenConnection(name string) (resp types.JSResp) {
 
 // CloseConnection close redis server connection
 func (b *browserService) CloseConnection(name string) (resp types.JSResp) {
+	b.stopTTLWatches(name)
 	if item, ok := b.connMap[name]; ok {
 		delete(b.connMap, name)
 		if item.cancelFunc != nil {
 			item.cancelFunc()
 		}
 		if item.client != nil {
-			item.client.Close()
+			Connection().releaseClient(name, item.client)
 		}
 	}
 	resp.Success = true
 	return
 }
 
-func (b *browserService) createRedisClient(ctx context.Context, selConn types.ConnectionConfig) (client redis.UniversalClient, err error) {
-	hook := redis2.NewHook(selConn.Name, func(cmd string, cost int64) {
+// record executed commands of client to history
+func (b *browserService) addHistoryHook(ctx context.Context, server string, client redis.UniversalClient) error {
+	hook := redis2.NewHook(server, func(cmd string, cost int64) {
 		now := time.Now()
 		//last := strings.LastIndex(cmd, ":")
 		//if last != -1 {
@@ -266,38 +322,25 @@ func (b *browserService) createRedisClient(ctx context.Context, selConn types.Co
 		//}
 		b.cmdHistory = append(b.cmdHistory, cmdHistoryItem{
 			Timestamp: now.UnixMilli(),
-			Server:    selConn.Name,
+			Server:    server,
 			Cmd:       cmd,
 			Cost:      cost,
 		})
 	})
 
-	client, err = Connection().createRedisClient(selConn)
-	if err != nil {
-		err = fmt.Errorf("create conenction error: %s", err.Error())
-		return
-	}
-
-	_ = client.Do(ctx, "CLIENT", "SETNAME", url.QueryEscape(selConn.Name)).Err()
 	// add hook to each node in cluster mode
 	if cluster, ok := client.(*redis.ClusterClient); ok {
-		err = cluster.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
+		err := cluster.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
 			cli.AddHook(hook)
 			return nil
 		})
 		if err != nil {
-			err = fmt.Errorf("get cluster nodes error: %s", err.Error())
-			return
+			return fmt.Errorf("get cluster nodes error: %s", err.Error())
 		}
 	} else {
 		client.AddHook(hook)
 	}
-
-	if _, err = client.Ping(ctx).Result(); err != nil && !errors.Is(err, redis.Nil) {
-		err = errors.New("can not connect to redis server:" + err.Error())
-		return
-	}
-	return
+	return nil
 }
 
 // get a redis client from local cache or create a new one
@@ -308,40 +351,39 @@ func (b *browserService) getRedisClient(server string, db int) (item *connection
 
 	var ok bool
 	var client redis.UniversalClient
-	if item, ok = b.connMap[server]; ok {
-		if item.db == db || db < 0 {
-			// return without switch database directly
-			return
-		}
-
-		// close previous connection if database is not the same
-		if item.cancelFunc != nil {
-			item.cancelFunc()
-		}
-		item.client.Close()
-		delete(b.connMap, server)
+	if item, ok = b.connMap[server]; ok && (item.db == db || db < 0) {
+		// return without switch database directly
+		return
 	}
 
-	// recreate new connection after switch database
+	// check profile before releasing previous connection, keep it alive if switching is rejected
 	selConn := Connection().getConnection(server)
 	if selConn == nil {
 		err = fmt.Errorf("no match connection \"%s\"", server)
-		delete(b.connMap, server)
+	} else if selConn.Cluster.Enable && db > 0 {
+		err = errors.New("SELECT not supported in cluster mode")
+	}
+	if err != nil {
+		item = nil
 		return
 	}
 
-	ctx, cancelFunc := context.WithCancel(b.ctx)
-	b.connMap[server] = &connectionItem{
-		ctx:        ctx,
-		cancelFunc: cancelFunc,
+	if prev, ok := b.connMap[server]; ok {
+		// release previous connection if database is not the same
+		if prev.cancelFunc != nil {
+			prev.cancelFunc()
+		}
+		Connection().releaseClient(server, prev.client)
+		delete(b.connMap, server)
 	}
-	var connConfig = selConn.ConnectionConfig
-	connConfig.LastDB = db
-	client, err = b.createRedisClient(ctx, connConfig)
+
+	// recreate new connection after switch database
+
+	client, err = Connection().acquireClient(b.ctx, server, db)
 	if err != nil {
-		delete(b.connMap, server)
 		return
 	}
+	ctx, cancelFunc := context.WithCancel(b.ctx)
 	item = &connectionItem{
 		client:      client,
 		ctx:         ctx,
@@ -358,6 +400,27 @@ func (b *browserService) getRedisClient(server string, db int) (item *connection
 	return
 }
 
+// SelectDB switch current database of connection, following operations without database specified will target to it
+func (b *browserService) SelectDB(server string, db int) (resp types.JSResp) {
+	if db < 0 {
+		resp.Msg = "invalid database index"
+		return
+	}
+	if _, err := b.getRedisClient(server, db); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	Connection().SaveLastDB(server, db)
+
+	resp.Success = true
+	resp.Data = struct {
+		DB int `json:"db"`
+	}{
+		DB: db,
+	}
+	return
+}
+
 // load current database size
 func (b *browserService) loadDBSize(ctx context.Context, client redis.UniversalClient) int64 {
 	keyCount, _ := client.DBSize(ctx).Result()
@@ -453,23 +516,88 @@ func (b *browserService) OpenDatabase(server string, db int) (resp types.JSResp)
 	return
 }
 
+// parse comma-separated key type filter, type with "!" prefix will be excluded
+func (b *browserService) parseTypeFilter(keyType string) (include, exclude []string) {
+	for _, t := range strings.Split(keyType, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if excluded := strings.HasPrefix(t, "!"); excluded {
+			if t = strings.TrimSpace(t[1:]); len(t) > 0 {
+				exclude = append(exclude, t)
+			}
+		} else if len(t) > 0 {
+			include = append(include, t)
+		}
+	}
+	return
+}
+
+// check if key type matched filter
+func (b *browserService) matchTypeFilter(t string, include, exclude []string) bool {
+	t = strings.ToLower(t)
+	if t == "none" || slices.Contains(exclude, t) {
+		return false
+	}
+	return len(include) <= 0 || slices.Contains(include, t)
+}
+
+// filter keys by type with pipelined TYPE queries
+func (b *browserService) filterKeysByType(ctx context.Context, cli redis.UniversalClient, keys []string, include, exclude []string) ([]string, error) {
+	if len(keys) <= 0 {
+		return keys, nil
+	}
+	pipe := cli.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	for i, k := range keys {
+		typeCmds[i] = pipe.Type(ctx, k)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	filtered := make([]string, 0, len(keys))
+	for i, k := range keys {
+		if b.matchTypeFilter(typeCmds[i].Val(), include, exclude) {
+			filtered = append(filtered, k)
+		}
+	}
+	return filtered, nil
+}
+
 // scan keys
+// COUNT of SCAN starts from the scan size in preferences, and doubles while iterations return few matched keys
+// if auto-tuning enabled, up to consts.MAX_SCAN_SIZE
+// @param keyType comma-separated key types, type with "!" prefix will be excluded
 // @return loaded keys
 // @return next cursor
+// @return effective COUNT of the last SCAN
 // @return scan error
-func (b *browserService) scanKeys(ctx context.Context, client redis.UniversalClient, match, keyType string, cursor uint64, count int64) ([]any, uint64, error) {
+func (b *browserService) scanKeys(ctx context.Context, client redis.UniversalClient, match, keyType string, cursor uint64, count int64) ([]any, uint64, int64, error) {
 	var err error
-	filterType := len(keyType) > 0
+	include, exclude := b.parseTypeFilter(keyType)
+	// filter by SCAN TYPE if only one type included, otherwise by pipelined TYPE
+	scanType := len(include) == 1 && len(exclude) <= 0
+	pipeFilter := !scanType && len(include)+len(exclude) > 0
 	scanSize := int64(Preferences().GetScanSize())
+	autoTune := Preferences().GetScanAutoTune()
+	var effectiveSize int64
+	var sizeMutex sync.Mutex
 	// define sub scan function
 	scan := func(ctx context.Context, cli redis.UniversalClient, count int64, appendFunc func(k []any)) error {
 		var loadedKey []string
 		var scanCount int64
+		size := scanSize
+		defer func() {
+			sizeMutex.Lock()
+			effectiveSize = max(effectiveSize, size)
+			sizeMutex.Unlock()
+		}()
 		for {
-			if filterType {
-				loadedKey, cursor, err = cli.ScanType(ctx, cursor, match, scanSize, keyType).Result()
+			if scanType {
+				loadedKey, cursor, err = cli.ScanType(ctx, cursor, match, size, include[0]).Result()
 			} else {
-				loadedKey, cursor, err = cli.Scan(ctx, cursor, match, scanSize).Result()
+				loadedKey, cursor, err = cli.Scan(ctx, cursor, match, size).Result()
+				if err == nil && pipeFilter {
+					loadedKey, err = b.filterKeysByType(ctx, cli, loadedKey, include, exclude)
+				}
 			}
 			if err != nil {
 				return err
@@ -484,6 +612,10 @@ func (b *browserService) scanKeys(ctx context.Context, client redis.UniversalCli
 			if (count > 0 && scanCount > count) || cursor == 0 {
 				break
 			}
+			// match rate is low, scan more in one iteration to reduce round trips
+			if autoTune && int64(len(loadedKey)) < size/10 && size < consts.MAX_SCAN_SIZE {
+				size = min(size*2, consts.MAX_SCAN_SIZE)
+			}
 		}
 		return nil
 	}
@@ -512,19 +644,162 @@ func (b *browserService) scanKeys(ctx context.Context, client redis.UniversalCli
 		})
 	}
 	if err != nil {
-		return keys, cursor, err
+		return keys, cursor, effectiveSize, err
+	}
+	return keys, cursor, effectiveSize, nil
+}
+
+// ScanKeys scan one page of keys by cursor, start a new scan with empty cursor
+// @param count hint of keys count in one page
+// @param keyType comma-separated key types to filter, type with "!" prefix will be excluded, no filter if empty
+// @param exact match pattern as literal key name, glob characters in it will be escaped
+// @return keys with their types, and the next cursor which is empty if scan finished
+func (b *browserService) ScanKeys(server string, db int, pattern string, count int64, cursor string, keyType string, exact bool) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if len(pattern) <= 0 {
+		pattern = "*"
+	} else if exact {
+		pattern = redis2.EscapeGlob(pattern)
+	}
+	if count <= 0 {
+		count = int64(Preferences().GetScanSize())
+	}
+
+	include, exclude := b.parseTypeFilter(keyType)
+	scan := func(ctx context.Context, cli redis.UniversalClient, cur uint64) ([]scanKeyItem, uint64, error) {
+		var loadedKeys []string
+		var err error
+		if len(include) == 1 && len(exclude) <= 0 {
+			loadedKeys, cur, err = cli.ScanType(ctx, cur, pattern, count, include[0]).Result()
+		} else {
+			loadedKeys, cur, err = cli.Scan(ctx, cur, pattern, count).Result()
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// query types of keys in pipeline
+		pipe := cli.Pipeline()
+		typeCmds := make([]*redis.StatusCmd, len(loadedKeys))
+		for i, k := range loadedKeys {
+			typeCmds[i] = pipe.Type(ctx, k)
+		}
+		if len(loadedKeys) > 0 {
+			if _, err = pipe.Exec(ctx); err != nil {
+				return nil, 0, err
+			}
+		}
+		keys := make([]scanKeyItem, 0, len(loadedKeys))
+		for i, k := range loadedKeys {
+			// key may be removed after scanned
+			if t := typeCmds[i].Val(); b.matchTypeFilter(t, include, exclude) {
+				keys = append(keys, scanKeyItem{
+					Key:  strutil.EncodeRedisKey(k),
+					Type: t,
+				})
+			}
+		}
+		return keys, cur, nil
+	}
+
+	var keys []scanKeyItem
+	var nextCursor string
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode, scan masters one by one, cursor is formatted as "cursor@addr"
+		var addrs []string
+		var mutex sync.Mutex
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			mutex.Lock()
+			addrs = append(addrs, cli.Options().Addr)
+			mutex.Unlock()
+			return nil
+		})
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		sort.Strings(addrs)
+		if len(addrs) <= 0 {
+			resp.Msg = "no master node found"
+			return
+		}
+
+		nodeIndex, nodeCursor := 0, uint64(0)
+		if len(cursor) > 0 {
+			cur, addr, _ := strings.Cut(cursor, "@")
+			if nodeCursor, err = strconv.ParseUint(cur, 10, 64); err != nil {
+				resp.Msg = "invalid cursor: " + cursor
+				return
+			}
+			if nodeIndex = slices.Index(addrs, addr); nodeIndex < 0 {
+				resp.Msg = "invalid cursor, cluster topology may have changed"
+				return
+			}
+		}
+
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			if cli.Options().Addr != addrs[nodeIndex] {
+				return nil
+			}
+			var serr error
+			keys, nodeCursor, serr = scan(ctx, cli, nodeCursor)
+			return serr
+		})
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		if nodeCursor == 0 {
+			nodeIndex += 1
+		}
+		if nodeIndex < len(addrs) {
+			nextCursor = fmt.Sprintf("%d@%s", nodeCursor, addrs[nodeIndex])
+		}
+	} else {
+		var cur uint64
+		if len(cursor) > 0 {
+			if cur, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+				resp.Msg = "invalid cursor: " + cursor
+				return
+			}
+		}
+		if keys, cur, err = scan(ctx, client, cur); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		if cur != 0 {
+			nextCursor = strconv.FormatUint(cur, 10)
+		}
 	}
-	return keys, cursor, nil
+
+	resp.Success = true
+	resp.Data = struct {
+		Keys   []scanKeyItem `json:"keys"`
+		Cursor string        `json:"cursor"`
+		End    bool          `json:"end"`
+	}{
+		Keys:   keys,
+		Cursor: nextCursor,
+		End:    len(nextCursor) <= 0,
+	}
+	return
 }
 
 // check if key exists
 func (b *browserService) existsKey(ctx context.Context, client redis.UniversalClient, key, keyType string) bool {
 	var keyExists atomic.Bool
+	include, exclude := b.parseTypeFilter(keyType)
 	if cluster, ok := client.(*redis.ClusterClient); ok {
 		// cluster mode
 		cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
 			if n := cli.Exists(ctx, key).Val(); n > 0 {
-				if len(keyType) <= 0 || strings.ToLower(keyType) == cli.Type(ctx, key).Val() {
+				if len(keyType) <= 0 || b.matchTypeFilter(cli.Type(ctx, key).Val(), include, exclude) {
 					keyExists.Store(true)
 				}
 			}
@@ -532,7 +807,7 @@ func (b *browserService) existsKey(ctx context.Context, client redis.UniversalCl
 		})
 	} else {
 		if n := client.Exists(ctx, key).Val(); n > 0 {
-			if len(keyType) <= 0 || strings.ToLower(keyType) == client.Type(ctx, key).Val() {
+			if len(keyType) <= 0 || b.matchTypeFilter(client.Type(ctx, key).Val(), include, exclude) {
 				keyExists.Store(true)
 			}
 		}
@@ -553,7 +828,7 @@ func (b *browserService) LoadNextKeys(server string, db int, match, keyType stri
 
 	client, ctx, count := item.client, item.ctx, item.stepSize
 	var matchKeys []any
-	var maxKeys int64
+	var maxKeys, scanSize int64
 	cursor := item.cursor[db]
 	fullScan := match == "*" || match == ""
 	if exactMatch && !fullScan {
@@ -563,7 +838,7 @@ func (b *browserService) LoadNextKeys(server string, db int, match, keyType stri
 		}
 		b.setClientCursor(server, db, 0)
 	} else {
-		matchKeys, cursor, err = b.scanKeys(ctx, client, match, keyType, cursor, count)
+		matchKeys, cursor, scanSize, err = b.scanKeys(ctx, client, match, keyType, cursor, count)
 		if err != nil {
 			resp.Msg = err.Error()
 			return
@@ -578,9 +853,10 @@ func (b *browserService) LoadNextKeys(server string, db int, match, keyType stri
 
 	resp.Success = true
 	resp.Data = map[string]any{
-		"keys":    matchKeys,
-		"end":     cursor == 0,
-		"maxKeys": maxKeys,
+		"keys":     matchKeys,
+		"end":      cursor == 0,
+		"maxKeys":  maxKeys,
+		"scanSize": scanSize, // effective COUNT of SCAN
 	}
 	return
 }
@@ -595,7 +871,7 @@ func (b *browserService) LoadNextAllKeys(server string, db int, match, keyType s
 
 	client, ctx := item.client, item.ctx
 	var matchKeys []any
-	var maxKeys int64
+	var maxKeys, scanSize int64
 	fullScan := match == "*" || match == ""
 	if exactMatch && !fullScan {
 		if b.existsKey(ctx, client, match, keyType) {
@@ -604,7 +880,7 @@ func (b *browserService) LoadNextAllKeys(server string, db int, match, keyType s
 		}
 	} else {
 		cursor := item.cursor[db]
-		matchKeys, _, err = b.scanKeys(ctx, client, match, keyType, cursor, 0)
+		matchKeys, _, scanSize, err = b.scanKeys(ctx, client, match, keyType, cursor, 0)
 		if err != nil {
 			resp.Msg = err.Error()
 			return
@@ -619,8 +895,9 @@ func (b *browserService) LoadNextAllKeys(server string, db int, match, keyType s
 
 	resp.Success = true
 	resp.Data = map[string]any{
-		"keys":    matchKeys,
-		"maxKeys": maxKeys,
+		"keys":     matchKeys,
+		"maxKeys":  maxKeys,
+		"scanSize": scanSize, // effective COUNT of SCAN
 	}
 	return
 }
@@ -635,13 +912,14 @@ func (b *browserService) LoadAllKeys(server string, db int, match, keyType strin
 
 	client, ctx := item.client, item.ctx
 	var matchKeys []any
+	var scanSize int64
 	fullScan := match == "*" || match == ""
 	if exactMatch && !fullScan {
 		if b.existsKey(ctx, client, match, keyType) {
 			matchKeys = []any{match}
 		}
 	} else {
-		matchKeys, _, err = b.scanKeys(ctx, client, match, keyType, 0, 0)
+		matchKeys, _, scanSize, err = b.scanKeys(ctx, client, match, keyType, 0, 0)
 		if err != nil {
 			resp.Msg = err.Error()
 			return
@@ -650,7 +928,8 @@ func (b *browserService) LoadAllKeys(server string, db int, match, keyType strin
 
 	resp.Success = true
 	resp.Data = map[string]any{
-		"keys": matchKeys,
+		"keys":     matchKeys,
+		"scanSize": scanSize, // effective COUNT of SCAN
 	}
 	return
 }
@@ -672,7 +951,7 @@ func (b *browserService) GetKeyType(param types.KeySummaryParam) (resp types.JSR
 	}
 
 	if keyType == "none" {
-		resp.Msg = "key not exists"
+		b.keyNotExists(&resp)
 		return
 	}
 
@@ -689,6 +968,25 @@ func (b *browserService) GetKeyType(param types.KeySummaryParam) (resp types.JSR
 	return
 }
 
+// mark response as key not exists, frontend can remove it from tree view instead of reporting error
+func (b *browserService) keyNotExists(resp *types.JSResp) {
+	resp.Success = false
+	resp.Code = types.RESP_CODE_KEY_NOT_EXISTS
+	resp.Msg = "key not exists"
+}
+
+// check if loaded value is nil, or empty slice and map
+func (b *browserService) isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() <= 0
+	}
+	return false
+}
+
 // GetKeySummary get key summary info
 func (b *browserService) GetKeySummary(param types.KeySummaryParam) (resp types.JSResp) {
 	item, err := b.getRedisClient(param.Server, param.DB)
@@ -719,7 +1017,7 @@ func (b *browserService) GetKeySummary(param types.KeySummaryParam) (resp types.
 		Size: size,
 	}
 	if data.Type == "none" {
-		resp.Msg = "key not exists"
+		b.keyNotExists(&resp)
 		return
 	}
 
@@ -763,605 +1061,3344 @@ func (b *browserService) GetKeySummary(param types.KeySummaryParam) (resp types.
 	return
 }
 
-// GetKeyDetail get key detail
-func (b *browserService) GetKeyDetail(param types.KeyDetailParam) (resp types.JSResp) {
-	item, err := b.getRedisClient(param.Server, param.DB)
-	if err != nil {
-		resp.Msg = err.Error()
-		return
+// convert error of MEMORY USAGE to capability error if not supported
+func (b *browserService) wrapMemoryUsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "unknown command") || strings.Contains(msg, "unknown subcommand") ||
+		strings.Contains(msg, "disabled") || strings.Contains(msg, "noperm") {
+		return fmt.Errorf("MEMORY USAGE is not supported or disabled on this server: %s", err.Error())
 	}
+	return err
+}
 
-	client, ctx, entryCors := item.client, item.ctx, item.entryCursor
-	key := strutil.DecodeRedisKey(param.Key)
-	var keyType string
-	keyType, err = client.Type(ctx, key).Result()
+// GetKeyMemory get memory usage of key in bytes
+func (b *browserService) GetKeyMemory(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
-	if keyType == "none" {
-		resp.Msg = "key not exists"
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	size, err := client.MemoryUsage(ctx, key, 0).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			resp.Msg = "key not exists"
+		} else {
+			resp.Msg = b.wrapMemoryUsageError(err).Error()
+		}
 		return
 	}
-	var doConvert bool
-	if (len(param.Decode) > 0 && param.Decode != types.DECODE_NONE) ||
-		(len(param.Format) > 0 && param.Format != types.FORMAT_RAW) {
-		doConvert = true
+
+	resp.Success = true
+	resp.Data = keyMemoryItem{
+		Key:  k,
+		Size: size,
 	}
+	return
+}
 
-	var data types.KeyDetail
-	data.KeyType = strings.ToLower(keyType)
-	//var cursor uint64
-	matchPattern := param.MatchPattern
-	if len(matchPattern) <= 0 {
-		matchPattern = "*"
+// GetObjectInfo get internal encoding, reference count, idle time and access frequency of key
+// IDLETIME is only available if maxmemory-policy is not LFU, and FREQ is only available if it is
+func (b *browserService) GetObjectInfo(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
 	}
 
-	// define get entry cursor function
-	getEntryCursor := func() (uint64, string, bool) {
-		if entry, ok := entryCors[param.DB]; !ok || entry.Key != key || entry.Pattern != matchPattern {
-			// not the same key or match pattern, reset cursor
-			entry = entryCursor{
-				DB:      param.DB,
-				Key:     key,
-				Pattern: matchPattern,
-				Cursor:  0,
-			}
-			entryCors[param.DB] = entry
-			return 0, "", true
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	encoding, err := client.ObjectEncoding(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			resp.Msg = "key not exists"
 		} else {
-			return entry.Cursor, entry.XLast, false
+			resp.Msg = err.Error()
 		}
+		return
 	}
-	// define set entry cursor function
-	setEntryCursor := func(cursor uint64) {
-		entryCors[param.DB] = entryCursor{
-			DB:      param.DB,
-			Type:    "",
-			Key:     key,
-			Pattern: matchPattern,
-			Cursor:  cursor,
-		}
+
+	info := struct {
+		Encoding string `json:"encoding"`
+		RefCount *int64 `json:"refCount,omitempty"`
+		IdleTime *int64 `json:"idleTime,omitempty"` // seconds
+		Freq     *int64 `json:"freq,omitempty"`     // logarithmic access frequency counter of LFU
+	}{
+		Encoding: encoding,
 	}
-	// define set last stream pos function
-	setEntryXLast := func(last string) {
-		entryCors[param.DB] = entryCursor{
-			DB:      param.DB,
-			Type:    "",
-			Key:     key,
-			Pattern: matchPattern,
-			XLast:   last,
-		}
+	if refCount, err := client.ObjectRefCount(ctx, key).Result(); err == nil {
+		info.RefCount = &refCount
+	}
+	if idle, err := client.ObjectIdleTime(ctx, key).Result(); err == nil {
+		idleTime := int64(idle.Seconds())
+		info.IdleTime = &idleTime
+	}
+	if freq, err := client.ObjectFreq(ctx, key).Result(); err == nil {
+		info.Freq = &freq
 	}
 
-	decoder := Preferences().GetDecoder()
+	resp.Success = true
+	resp.Data = info
+	return
+}
 
-	switch data.KeyType {
-	case "string":
-		var str string
-		str, err = client.Get(ctx, key).Result()
-		data.Value = strutil.EncodeRedisKey(str)
-		//data.Value, data.Decode, data.Format = convutil.ConvertTo(str, param.Decode, param.Format, decoder)
+type optimizationHint struct {
+	Severity string `json:"severity"` // info/warning/critical
+	Message  string `json:"message"`
+}
 
-	case "list":
-		loadListHandle := func() ([]types.ListEntryItem, bool, bool, error) {
-			var loadVal []string
-			var cursor uint64
-			var reset bool
-			var subErr error
-			doFilter := matchPattern != "*"
-			if param.Full || doFilter {
-				// load all
-				cursor, reset = 0, true
-				loadVal, subErr = client.LRange(ctx, key, 0, -1).Result()
-			} else {
-				if param.Reset {
-					cursor, reset = 0, true
-				} else {
-					cursor, _, reset = getEntryCursor()
-				}
-				scanSize := int64(Preferences().GetScanSize())
-				loadVal, subErr = client.LRange(ctx, key, int64(cursor), int64(cursor)+scanSize-1).Result()
-				cursor = cursor + uint64(scanSize)
-				if len(loadVal) < int(scanSize) {
-					cursor = 0
-				}
-			}
-			setEntryCursor(cursor)
+// OptimizationHints suggest optimization of key by its type, internal encoding, length and memory usage,
+// compared to encoding thresholds configured on server
+func (b *browserService) OptimizationHints(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
 
-			items := make([]types.ListEntryItem, 0, len(loadVal))
-			for _, val := range loadVal {
-				if doFilter && !strings.Contains(val, param.MatchPattern) {
-					continue
-				}
-				items = append(items, types.ListEntryItem{
-					Index: len(items),
-					Value: strutil.EncodeRedisKey(val),
-				})
-				if doConvert {
-					if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
-						items[len(items)-1].DisplayValue = dv
-					}
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if keyType == "none" {
+		b.keyNotExists(&resp)
+		return
+	}
+	encoding, err := client.ObjectEncoding(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	length, _ := b.getKeyLength(ctx, client, key, keyType)
+	memory, _ := client.MemoryUsage(ctx, key, 0).Result()
+
+	// get threshold from config, the old name with "ziplist" is used before redis 7.0
+	getConfig := func(name string, defaultVal int64) int64 {
+		for _, n := range []string{name, strings.Replace(name, "listpack", "ziplist", 1)} {
+			if conf, confErr := client.ConfigGet(ctx, n).Result(); confErr == nil {
+				if val, parseErr := strconv.ParseInt(conf[n], 10, 64); parseErr == nil {
+					return val
 				}
 			}
-			if subErr != nil {
-				return items, reset, false, subErr
-			}
-			return items, reset, cursor == 0, nil
 		}
-
-		data.Value, data.Reset, data.End, err = loadListHandle()
-		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
-		if err != nil {
-			resp.Msg = err.Error()
-			return
+		return defaultVal
+	}
+	// max bytes of sampled elements
+	maxElementSize := func(elements []string) (size int) {
+		for _, elem := range elements {
+			size = max(size, len(elem))
 		}
+		return
+	}
+	const sampleSize = 20
+	const bigLength = 10000
+	const bigMemory = 10 * 1024 * 1024
 
-	case "hash":
-		if !strings.HasPrefix(matchPattern, "*") {
-			matchPattern = "*" + matchPattern
-		}
-		if !strings.HasSuffix(matchPattern, "*") {
-			matchPattern = matchPattern + "*"
+	hints := []optimizationHint{}
+	addHint := func(severity, format string, args ...any) {
+		hints = append(hints, optimizationHint{Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	switch keyType {
+	case "string":
+		switch encoding {
+		case "int":
+			addHint("info", "value is stored as integer, which is the most compact encoding")
+		case "raw":
+			if memory > 1024*1024 {
+				addHint("warning", "string value is larger than 1MB, consider compressing it or splitting into smaller keys")
+			}
 		}
-		loadHashHandle := func() ([]types.HashEntryItem, bool, bool, error) {
-			var items []types.HashEntryItem
-			var loadedVal []string
-			var cursor uint64
-			var reset bool
-			var subErr error
-			scanSize := int64(Preferences().GetScanSize())
-			if param.Full || matchPattern != "*" {
-				// load all
-				cursor, reset = 0, true
-				items = []types.HashEntryItem{}
-				for {
-					loadedVal, cursor, subErr = client.HScan(ctx, key, cursor, matchPattern, scanSize).Result()
-					if subErr != nil {
-						return nil, reset, false, subErr
-					}
-					for i := 0; i < len(loadedVal); i += 2 {
-						items = append(items, types.HashEntryItem{
-							Key:   loadedVal[i],
-							Value: strutil.EncodeRedisKey(loadedVal[i+1]),
-						})
-						if doConvert {
-							if dv, _, _ := convutil.ConvertTo(loadedVal[i+1], param.Decode, param.Format, decoder); dv != loadedVal[i+1] {
-								items[len(items)-1].DisplayValue = dv
-							}
-						}
-					}
-					if cursor == 0 {
-						break
+	case "hash", "zset":
+		maxEntries := getConfig(keyType+"-max-listpack-entries", 128)
+		maxValue := getConfig(keyType+"-max-listpack-value", 64)
+		compact := encoding == "listpack" || encoding == "ziplist"
+		if !compact {
+			var samples []string
+			if keyType == "hash" {
+				if pairs, pairErr := client.HRandFieldWithValues(ctx, key, sampleSize).Result(); pairErr == nil {
+					for _, pair := range pairs {
+						samples = append(samples, pair.Key, pair.Value)
 					}
 				}
 			} else {
-				if param.Reset {
-					cursor, reset = 0, true
-				} else {
-					cursor, _, reset = getEntryCursor()
-				}
-				loadedVal, cursor, subErr = client.HScan(ctx, key, cursor, matchPattern, scanSize).Result()
-				if subErr != nil {
-					return nil, reset, false, subErr
-				}
-				loadedLen := len(loadedVal)
-				items = make([]types.HashEntryItem, loadedLen/2)
-				for i := 0; i < loadedLen; i += 2 {
-					items[i/2].Key = loadedVal[i]
-					items[i/2].Value = strutil.EncodeRedisKey(loadedVal[i+1])
-					if doConvert {
-						if dv, _, _ := convutil.ConvertTo(loadedVal[i+1], param.Decode, param.Format, decoder); dv != loadedVal[i+1] {
-							items[i/2].DisplayValue = dv
-						}
-					}
+				samples, _ = client.ZRandMember(ctx, key, sampleSize).Result()
+			}
+			if length > maxEntries {
+				addHint("info", "%s has %d elements, exceeds %s-max-listpack-entries (%d), consider sharding into smaller keys to use compact listpack encoding",
+					keyType, length, keyType, maxEntries)
+			} else if size := maxElementSize(samples); int64(size) > maxValue {
+				addHint("info", "%s uses %s encoding because some elements are larger than %s-max-listpack-value (%d bytes), listpack could be used if elements were smaller",
+					keyType, encoding, keyType, maxValue)
+			}
+		}
+	case "set":
+		maxIntset := getConfig("set-max-intset-entries", 512)
+		if encoding == "hashtable" {
+			members, _ := client.SRandMemberN(ctx, key, sampleSize).Result()
+			allInteger := len(members) > 0
+			for _, m := range members {
+				if _, parseErr := strconv.ParseInt(m, 10, 64); parseErr != nil {
+					allInteger = false
+					break
 				}
 			}
-			setEntryCursor(cursor)
-			return items, reset, cursor == 0, nil
+			if allInteger && length > maxIntset {
+				addHint("info", "set of integers has %d members, exceeds set-max-intset-entries (%d), consider sharding to use compact intset encoding",
+					length, maxIntset)
+			}
 		}
-
-		data.Value, data.Reset, data.End, err = loadHashHandle()
-		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
-		if err != nil {
-			resp.Msg = err.Error()
-			return
+	case "list":
+		if encoding == "quicklist" && length <= 128 {
+			addHint("info", "list has only %d elements but uses quicklist encoding, listpack could be used if elements were smaller", length)
 		}
-
-	case "set":
-		if !strings.HasPrefix(matchPattern, "*") {
-			matchPattern = "*" + matchPattern
+	case "stream":
+		if length > bigLength {
+			addHint("warning", "stream has %d entries, consider trimming by XTRIM or MAXLEN of XADD", length)
 		}
-		if !strings.HasSuffix(matchPattern, "*") {
-			matchPattern = matchPattern + "*"
+	}
+	if keyType != "string" && keyType != "stream" {
+		if length > bigLength*10 {
+			addHint("critical", "%s has %d elements, operations on whole key like DEL or HGETALL may block server, consider splitting it", keyType, length)
+		} else if length > bigLength {
+			addHint("warning", "%s has %d elements, avoid reading whole key at once, use SCAN family commands instead", keyType, length)
 		}
-		loadSetHandle := func() ([]types.SetEntryItem, bool, bool, error) {
-			var items []types.SetEntryItem
-			var cursor uint64
-			var reset bool
-			var subErr error
-			var loadedKey []string
-			scanSize := int64(Preferences().GetScanSize())
-			if param.Full || matchPattern != "*" {
-				// load all
-				cursor, reset = 0, true
-				items = []types.SetEntryItem{}
-				for {
-					loadedKey, cursor, subErr = client.SScan(ctx, key, cursor, matchPattern, scanSize).Result()
-					if subErr != nil {
-						return items, reset, false, subErr
-					}
-					for _, val := range loadedKey {
-						items = append(items, types.SetEntryItem{
-							Value: strutil.EncodeRedisKey(val),
-						})
-						if doConvert {
-							if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
-								items[len(items)-1].DisplayValue = dv
-							}
-						}
-					}
-					if cursor == 0 {
-						break
-					}
+	}
+	if memory > bigMemory {
+		addHint("warning", "key uses %d MB memory, consider splitting it and deleting by UNLINK to avoid blocking", memory/1024/1024)
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Type     string             `json:"type"`
+		Encoding string             `json:"encoding"`
+		Length   int64              `json:"length"`
+		Memory   int64              `json:"memory"`
+		Hints    []optimizationHint `json:"hints"`
+	}{
+		Type:     keyType,
+		Encoding: encoding,
+		Length:   length,
+		Memory:   memory,
+		Hints:    hints,
+	}
+	return
+}
+
+// GetTopMemoryKeys sample keys by SCAN and get the largest keys sorted by memory usage
+// @param sampleSize max count of keys to sample
+// @param topN count of keys to return
+func (b *browserService) GetTopMemoryKeys(server string, db int, pattern string, sampleSize int64, topN int) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if len(pattern) <= 0 {
+		pattern = "*"
+	}
+	if sampleSize <= 0 {
+		sampleSize = int64(Preferences().GetScanSize())
+	}
+	ks, _, _, err := b.scanKeys(ctx, client, pattern, "", 0, sampleSize)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	const batchSize = 1000
+	items := make([]keyMemoryItem, 0, len(ks))
+	for i := 0; i < len(ks); i += batchSize {
+		batch := ks[i:min(i+batchSize, len(ks))]
+		pipe := client.Pipeline()
+		cmds := make([]*redis.IntCmd, len(batch))
+		for j, k := range batch {
+			cmds[j] = pipe.MemoryUsage(ctx, strutil.DecodeRedisKey(k), 0)
+		}
+		pipe.Exec(ctx)
+		for j, cmd := range cmds {
+			if cmd.Err() != nil {
+				if errors.Is(cmd.Err(), redis.Nil) {
+					// key removed after scanned
+					continue
 				}
+				resp.Msg = b.wrapMemoryUsageError(cmd.Err()).Error()
+				return
+			}
+			items = append(items, keyMemoryItem{
+				Key:  batch[j],
+				Size: cmd.Val(),
+			})
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Size > items[j].Size
+	})
+	if topN > 0 && len(items) > topN {
+		items = items[:topN]
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Sampled int             `json:"sampled"`
+		Keys    []keyMemoryItem `json:"keys"`
+	}{
+		Sampled: len(ks),
+		Keys:    items,
+	}
+	return
+}
+
+// HotKeys sample keys by SCAN and get the hottest keys, sorted by OBJECT FREQ descending if maxmemory-policy is LFU,
+// otherwise sorted by OBJECT IDLETIME ascending
+// @param sampleSize max count of keys to sample
+// @param topN count of keys to return
+func (b *browserService) HotKeys(server string, db int, sampleSize int64, topN int) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if sampleSize <= 0 {
+		sampleSize = int64(Preferences().GetScanSize())
+	}
+	ks, _, _, err := b.scanKeys(ctx, client, "*", "", 0, sampleSize)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	// choose metric by eviction policy, try both if policy is unknown
+	metrics := []string{"freq", "idletime"}
+	if conf, confErr := client.ConfigGet(ctx, "maxmemory-policy").Result(); confErr == nil {
+		if policy, ok := conf["maxmemory-policy"]; ok {
+			if strings.Contains(policy, "lfu") {
+				metrics = []string{"freq"}
 			} else {
-				if param.Reset {
-					cursor, reset = 0, true
+				metrics = []string{"idletime"}
+			}
+		}
+	}
+	collect := func(metric string) ([]hotKeyItem, error) {
+		const batchSize = 1000
+		items := make([]hotKeyItem, 0, len(ks))
+		for i := 0; i < len(ks); i += batchSize {
+			batch := ks[i:min(i+batchSize, len(ks))]
+			pipe := client.Pipeline()
+			freqCmds := make([]*redis.IntCmd, len(batch))
+			idleCmds := make([]*redis.DurationCmd, len(batch))
+			for j, k := range batch {
+				if metric == "freq" {
+					freqCmds[j] = pipe.ObjectFreq(ctx, strutil.DecodeRedisKey(k))
 				} else {
-					cursor, _, reset = getEntryCursor()
+					idleCmds[j] = pipe.ObjectIdleTime(ctx, strutil.DecodeRedisKey(k))
 				}
-				loadedKey, cursor, subErr = client.SScan(ctx, key, cursor, matchPattern, scanSize).Result()
-				items = make([]types.SetEntryItem, len(loadedKey))
-				for i, val := range loadedKey {
-					items[i].Value = strutil.EncodeRedisKey(val)
-					if doConvert {
-						if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
-							items[i].DisplayValue = dv
-						}
+			}
+			pipe.Exec(ctx)
+			for j := range batch {
+				var score int64
+				var cmdErr error
+				if metric == "freq" {
+					score, cmdErr = freqCmds[j].Result()
+				} else {
+					var idle time.Duration
+					idle, cmdErr = idleCmds[j].Result()
+					score = int64(idle.Seconds())
+				}
+				if cmdErr != nil {
+					if errors.Is(cmdErr, redis.Nil) {
+						// key removed after scanned
+						continue
 					}
+					return nil, cmdErr
 				}
+				items = append(items, hotKeyItem{
+					Key:   batch[j],
+					Score: score,
+				})
 			}
-			setEntryCursor(cursor)
-			return items, reset, cursor == 0, nil
 		}
+		return items, nil
+	}
 
-		data.Value, data.Reset, data.End, err = loadSetHandle()
-		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
-		if err != nil {
-			resp.Msg = err.Error()
-			return
+	metric := "none"
+	var items []hotKeyItem
+	var warning string
+	for _, m := range metrics {
+		if items, err = collect(m); err == nil {
+			metric = m
+			break
+		}
+		warning = err.Error()
+	}
+	if metric == "none" {
+		items = []hotKeyItem{}
+		warning = "neither OBJECT FREQ nor OBJECT IDLETIME is available: " + warning
+	} else {
+		warning = ""
+		sort.Slice(items, func(i, j int) bool {
+			if metric == "freq" {
+				return items[i].Score > items[j].Score
+			}
+			return items[i].Score < items[j].Score
+		})
+		if topN > 0 && len(items) > topN {
+			items = items[:topN]
 		}
+	}
 
-	case "zset":
-		if !strings.HasPrefix(matchPattern, "*") {
-			matchPattern = "*" + matchPattern
-		}
-		if !strings.HasSuffix(matchPattern, "*") {
-			matchPattern = matchPattern + "*"
-		}
-		loadZSetHandle := func() ([]types.ZSetEntryItem, bool, bool, error) {
-			var items []types.ZSetEntryItem
-			var reset bool
-			var cursor uint64
-			scanSize := int64(Preferences().GetScanSize())
-			doFilter := matchPattern != "*"
-			if param.Full || doFilter {
-				// load all
-				var loadedVal []string
-				cursor, reset = 0, true
-				items = []types.ZSetEntryItem{}
-				for {
-					loadedVal, cursor, err = client.ZScan(ctx, key, cursor, matchPattern, scanSize).Result()
-					if err != nil {
-						return items, reset, false, err
-					}
-					var score float64
-					for i := 0; i < len(loadedVal); i += 2 {
-						if score, err = strconv.ParseFloat(loadedVal[i+1], 64); err == nil {
-							items = append(items, types.ZSetEntryItem{
-								Value: strutil.EncodeRedisKey(loadedVal[i]),
-								Score: score,
-							})
-							if doConvert {
-								if dv, _, _ := convutil.ConvertTo(loadedVal[i], param.Decode, param.Format, decoder); dv != loadedVal[i] {
-									items[len(items)-1].DisplayValue = dv
-								}
-							}
-						}
+	resp.Success = true
+	resp.Data = struct {
+		Metric  string       `json:"metric"` // freq/idletime/none
+		Sampled int          `json:"sampled"`
+		Keys    []hotKeyItem `json:"keys"`
+		Warning string       `json:"warning,omitempty"`
+	}{
+		Metric:  metric,
+		Sampled: len(ks),
+		Keys:    items,
+		Warning: warning,
+	}
+	return
+}
+
+// ScanBigKeys scan all keys and report the keys which size exceed threshold of its type
+// size means length for string, element count for hash/list/set/zset/stream, and memory usage for other types
+// @param thresholds threshold for each type, keys of type not present will be ignored
+// @param serialNo identify of this scanning, progress will be emitted by event "bigkeys:<serialNo>"
+func (b *browserService) ScanBigKeys(server string, db int, thresholds map[string]int64, serialNo string) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
+
+	cancelStopEvent := runtime.EventsOnce(ctx, "bigkeys:stop:"+serialNo, func(data ...any) {
+		cancelFunc()
+	})
+	defer cancelStopEvent()
+
+	processEvent := "bigkeys:" + serialNo
+	var scanned int64
+	var bigKeys = make([]bigKeyItem, 0)
+	var mutex sync.Mutex
+	lastEmit := time.Now()
+	emitProgress := func() {
+		runtime.EventsEmit(ctx, processEvent, map[string]any{
+			"scanned": scanned,
+			"found":   len(bigKeys),
+		})
+	}
+	scan := func(ctx context.Context, cli redis.UniversalClient) error {
+		scanSize := int64(Preferences().GetScanSize())
+		var cursor uint64
+		for {
+			loadedKeys, nextCursor, scanErr := cli.Scan(ctx, cursor, "*", scanSize).Result()
+			if scanErr != nil {
+				return scanErr
+			}
+			cursor = nextCursor
+
+			if len(loadedKeys) > 0 {
+				// query types first, then sizes
+				pipe := cli.Pipeline()
+				typeCmds := make([]*redis.StatusCmd, len(loadedKeys))
+				for i, k := range loadedKeys {
+					typeCmds[i] = pipe.Type(ctx, k)
+				}
+				if _, execErr := pipe.Exec(ctx); execErr != nil {
+					return execErr
+				}
+
+				pipe = cli.Pipeline()
+				sizeCmds := make([]*redis.IntCmd, len(loadedKeys))
+				for i, k := range loadedKeys {
+					keyType := typeCmds[i].Val()
+					if _, ok := thresholds[keyType]; !ok {
+						continue
 					}
-					if cursor == 0 {
-						break
+					switch keyType {
+					case "string":
+						sizeCmds[i] = pipe.StrLen(ctx, k)
+					case "hash":
+						sizeCmds[i] = pipe.HLen(ctx, k)
+					case "list":
+						sizeCmds[i] = pipe.LLen(ctx, k)
+					case "set":
+						sizeCmds[i] = pipe.SCard(ctx, k)
+					case "zset":
+						sizeCmds[i] = pipe.ZCard(ctx, k)
+					case "stream":
+						sizeCmds[i] = pipe.XLen(ctx, k)
+					default:
+						sizeCmds[i] = pipe.MemoryUsage(ctx, k, 0)
 					}
 				}
-			} else {
-				if param.Reset {
-					cursor, reset = 0, true
-				} else {
-					cursor, _, reset = getEntryCursor()
+				if pipe.Len() > 0 {
+					pipe.Exec(ctx)
 				}
-				var loadedVal []redis.Z
-				loadedVal, err = client.ZRangeWithScores(ctx, key, int64(cursor), int64(cursor)+scanSize-1).Result()
-				cursor = cursor + uint64(scanSize)
-				if len(loadedVal) < int(scanSize) {
-					cursor = 0
+				if ctx.Err() != nil {
+					return ctx.Err()
 				}
 
-				items = make([]types.ZSetEntryItem, 0, len(loadedVal))
-				for _, z := range loadedVal {
-					val := strutil.AnyToString(z.Member, "", 0)
-					if doFilter && !strings.Contains(val, param.MatchPattern) {
+				mutex.Lock()
+				scanned += int64(len(loadedKeys))
+				for i, cmd := range sizeCmds {
+					if cmd == nil || cmd.Err() != nil {
 						continue
 					}
-					entry := types.ZSetEntryItem{
-						Value: strutil.EncodeRedisKey(val),
-					}
-					if math.IsInf(z.Score, 1) {
-						entry.ScoreStr = "+inf"
-					} else if math.IsInf(z.Score, -1) {
-						entry.ScoreStr = "-inf"
-					} else {
-						entry.Score = z.Score
-					}
-					items = append(items, entry)
-					if doConvert {
-						if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
-							items[len(items)-1].DisplayValue = dv
-						}
+					keyType := typeCmds[i].Val()
+					if size := cmd.Val(); size >= thresholds[keyType] {
+						bigKeys = append(bigKeys, bigKeyItem{
+							Key:  strutil.EncodeRedisKey(loadedKeys[i]),
+							Type: keyType,
+							Size: size,
+						})
 					}
 				}
+				if time.Since(lastEmit).Milliseconds() > 100 {
+					lastEmit = time.Now()
+					emitProgress()
+				}
+				mutex.Unlock()
 			}
-			setEntryCursor(cursor)
-			return items, reset, cursor == 0, nil
-		}
 
-		data.Value, data.Reset, data.End, err = loadZSetHandle()
-		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
-		if err != nil {
-			resp.Msg = err.Error()
-			return
+			if cursor == 0 {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 		}
+	}
 
-	case "stream":
-		loadStreamHandle := func() ([]types.StreamEntryItem, bool, bool, error) {
-			var msgs []redis.XMessage
-			var last string
-			var reset bool
-			doFilter := matchPattern != "*"
-			if param.Full || doFilter {
-				// load all
-				last, reset = "", true
-				msgs, err = client.XRevRange(ctx, key, "+", "-").Result()
-			} else {
-				scanSize := int64(Preferences().GetScanSize())
-				if param.Reset {
-					last = ""
-				} else {
-					_, last, reset = getEntryCursor()
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return scan(ctx, cli)
+		})
+	} else {
+		err = scan(ctx, client)
+	}
+	emitProgress()
+
+	canceled := errors.Is(err, context.Canceled)
+	if err != nil && !canceled {
+		resp.Msg = err.Error()
+		return
+	}
+
+	sort.Slice(bigKeys, func(i, j int) bool {
+		return bigKeys[i].Size > bigKeys[j].Size
+	})
+	resp.Success = true
+	resp.Data = struct {
+		Canceled bool         `json:"canceled"`
+		Scanned  int64        `json:"scanned"`
+		Keys     []bigKeyItem `json:"keys"`
+	}{
+		Canceled: canceled,
+		Scanned:  scanned,
+		Keys:     bigKeys,
+	}
+	return
+}
+
+// FindKey search keys matched pattern in all databases, progress will be emitted by event "findkey:"+serialNo
+// and it can be canceled by event "findkey:stop:"+serialNo. only database 0 is searched in cluster mode
+// @param exact match pattern as literal key name, glob characters in it will be escaped
+func (b *browserService) FindKey(server string, pattern string, exact bool, serialNo string) (resp types.JSResp) {
+	if len(pattern) <= 0 {
+		resp.Msg = "pattern is required"
+		return
+	}
+	if exact {
+		pattern = redis2.EscapeGlob(pattern)
+	}
+
+	// search on shared client to avoid switching database of browser
+	client, err := Connection().acquireClient(b.ctx, server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	defer Connection().releaseClient(server, client)
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
+
+	cancelStopEvent := runtime.EventsOnce(ctx, "findkey:stop:"+serialNo, func(data ...any) {
+		cancelFunc()
+	})
+	defer cancelStopEvent()
+
+	type foundKeyItem struct {
+		DB   int    `json:"db"`
+		Key  any    `json:"key"`
+		Type string `json:"type"`
+	}
+	processEvent := "findkey:" + serialNo
+	var currentDB int
+	var scanned int64
+	var found = make([]foundKeyItem, 0)
+	var mutex sync.Mutex
+	lastEmit := time.Now()
+	emitProgress := func() {
+		runtime.EventsEmit(ctx, processEvent, map[string]any{
+			"db":      currentDB,
+			"scanned": scanned,
+			"found":   len(found),
+		})
+	}
+	scan := func(ctx context.Context, cli redis.Cmdable, db int) error {
+		scanSize := int64(Preferences().GetScanSize())
+		var cursor uint64
+		for {
+			loadedKeys, nextCursor, scanErr := cli.Scan(ctx, cursor, pattern, scanSize).Result()
+			if scanErr != nil {
+				return scanErr
+			}
+			cursor = nextCursor
+
+			if len(loadedKeys) > 0 {
+				pipe := cli.Pipeline()
+				typeCmds := make([]*redis.StatusCmd, len(loadedKeys))
+				for i, k := range loadedKeys {
+					typeCmds[i] = pipe.Type(ctx, k)
 				}
-				if len(last) <= 0 {
-					last = "+"
+				if _, execErr := pipe.Exec(ctx); execErr != nil {
+					return execErr
 				}
-				if last != "+" {
-					// add 1 more item when continue scan
-					msgs, err = client.XRevRangeN(ctx, key, last, "-", scanSize+1).Result()
-					msgs = msgs[1:]
-				} else {
-					msgs, err = client.XRevRangeN(ctx, key, last, "-", scanSize).Result()
+
+				mutex.Lock()
+				scanned += int64(len(loadedKeys))
+				for i, k := range loadedKeys {
+					if t := typeCmds[i].Val(); t != "none" {
+						found = append(found, foundKeyItem{
+							DB:   db,
+							Key:  strutil.EncodeRedisKey(k),
+							Type: t,
+						})
+					}
 				}
-				scanCount := len(msgs)
-				if scanCount <= 0 || scanCount < int(scanSize) {
-					last = ""
-				} else if scanCount > 0 {
-					last = msgs[scanCount-1].ID
+				if time.Since(lastEmit).Milliseconds() > 100 {
+					lastEmit = time.Now()
+					emitProgress()
 				}
+				mutex.Unlock()
 			}
-			setEntryXLast(last)
-			items := make([]types.StreamEntryItem, 0, len(msgs))
-			for _, msg := range msgs {
-				it := types.StreamEntryItem{
-					ID:    msg.ID,
-					Value: msg.Values,
-				}
-				if vb, merr := json.Marshal(msg.Values); merr != nil {
-					it.DisplayValue = "{}"
-				} else {
-					it.DisplayValue, _, _ = convutil.ConvertTo(string(vb), types.DECODE_NONE, types.FORMAT_JSON, decoder)
-				}
-				if doFilter && !strings.Contains(it.DisplayValue, param.MatchPattern) {
+
+			if cursor == 0 {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+
+	var totalDB int
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		totalDB = 1
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return scan(ctx, cli, 0)
+		})
+	} else if rdb, ok := client.(*redis.Client); ok {
+		totalDB = 1
+		if config, cerr := rdb.ConfigGet(ctx, "databases").Result(); cerr == nil {
+			if total, cerr := strconv.Atoi(config["databases"]); cerr == nil && total > 0 {
+				totalDB = total
+			}
+		}
+		// skip empty databases
+		var keyspace map[string]string
+		if res, ierr := rdb.Info(ctx, "keyspace").Result(); ierr == nil {
+			keyspace = b.parseInfo(res)["Keyspace"]
+		}
+
+		// switch database on a dedicated connection, and switch back before returning it to the shared pool
+		conn := rdb.Conn()
+		defer func() {
+			_ = conn.Select(b.ctx, rdb.Options().DB).Err()
+			conn.Close()
+		}()
+		for db := 0; db < totalDB && err == nil; db++ {
+			if keyspace != nil {
+				if _, ok := keyspace["db"+strconv.Itoa(db)]; !ok {
 					continue
 				}
-				items = append(items, it)
 			}
-			if err != nil {
-				return items, reset, false, err
+			mutex.Lock()
+			currentDB = db
+			mutex.Unlock()
+			if err = conn.Select(ctx, db).Err(); err == nil {
+				err = scan(ctx, conn, db)
 			}
-			return items, reset, last == "", nil
 		}
+	}
+	emitProgress()
+
+	canceled := errors.Is(err, context.Canceled)
+	if err != nil && !canceled {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Canceled bool           `json:"canceled"`
+		Scanned  int64          `json:"scanned"`
+		TotalDB  int            `json:"totalDB"`
+		Keys     []foundKeyItem `json:"keys"`
+	}{
+		Canceled: canceled,
+		Scanned:  scanned,
+		TotalDB:  totalDB,
+		Keys:     found,
+	}
+	return
+}
+
+// GetKeyDetail get key detail
+func (b *browserService) GetKeyDetail(param types.KeyDetailParam) (resp types.JSResp) {
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx, entryCors := item.client, item.ctx, item.entryCursor
+	key := strutil.DecodeRedisKey(param.Key)
+	var keyType string
+	keyType, err = client.Type(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	if keyType == "none" {
+		b.keyNotExists(&resp)
+		return
+	}
+	var doConvert bool
+	if (len(param.Decode) > 0 && param.Decode != types.DECODE_NONE) ||
+		(len(param.Format) > 0 && param.Format != types.FORMAT_RAW) {
+		doConvert = true
+	}
+
+	var data types.KeyDetail
+	data.KeyType = strings.ToLower(keyType)
+	// key may be expired or deleted after TYPE, then value is loaded as nil or empty
+	defer func() {
+		if len(resp.Code) > 0 || (resp.Success && !(data.Reset && b.isEmptyValue(data.Value))) {
+			return
+		}
+		if n, existsErr := client.Exists(ctx, key).Result(); existsErr == nil && n == 0 {
+			resp.Data = nil
+			b.keyNotExists(&resp)
+		}
+	}()
+	//var cursor uint64
+	matchPattern := param.MatchPattern
+	if len(matchPattern) <= 0 {
+		matchPattern = "*"
+	}
+
+	// define get entry cursor function
+	getEntryCursor := func() (uint64, string, bool) {
+		if entry, ok := entryCors[param.DB]; !ok || entry.Key != key || entry.Pattern != matchPattern {
+			// not the same key or match pattern, reset cursor
+			entry = entryCursor{
+				DB:      param.DB,
+				Key:     key,
+				Pattern: matchPattern,
+				Cursor:  0,
+			}
+			entryCors[param.DB] = entry
+			return 0, "", true
+		} else {
+			return entry.Cursor, entry.XLast, false
+		}
+	}
+	// define set entry cursor function
+	setEntryCursor := func(cursor uint64) {
+		entryCors[param.DB] = entryCursor{
+			DB:      param.DB,
+			Type:    "",
+			Key:     key,
+			Pattern: matchPattern,
+			Cursor:  cursor,
+		}
+	}
+	// define set last stream pos function
+	setEntryXLast := func(last string) {
+		entryCors[param.DB] = entryCursor{
+			DB:      param.DB,
+			Type:    "",
+			Key:     key,
+			Pattern: matchPattern,
+			XLast:   last,
+		}
+	}
+
+	decoder := Preferences().GetDecoder()
+
+	switch data.KeyType {
+	case "string":
+		var str string
+		str, err = client.Get(ctx, key).Result()
+		data.Value = strutil.EncodeRedisKey(str)
+		//data.Value, data.Decode, data.Format = convutil.ConvertTo(str, param.Decode, param.Format, decoder)
+
+	case "list":
+		loadListHandle := func() ([]types.ListEntryItem, bool, bool, error) {
+			var loadVal []string
+			var cursor uint64
+			var reset bool
+			var subErr error
+			doFilter := matchPattern != "*"
+			if param.Full || doFilter {
+				// load all
+				cursor, reset = 0, true
+				loadVal, subErr = client.LRange(ctx, key, 0, -1).Result()
+			} else {
+				if param.Reset {
+					cursor, reset = 0, true
+				} else {
+					cursor, _, reset = getEntryCursor()
+				}
+				scanSize := int64(Preferences().GetScanSize())
+				loadVal, subErr = client.LRange(ctx, key, int64(cursor), int64(cursor)+scanSize-1).Result()
+				cursor = cursor + uint64(scanSize)
+				if len(loadVal) < int(scanSize) {
+					cursor = 0
+				}
+			}
+			setEntryCursor(cursor)
+
+			items := make([]types.ListEntryItem, 0, len(loadVal))
+			for _, val := range loadVal {
+				if doFilter && !strings.Contains(val, param.MatchPattern) {
+					continue
+				}
+				items = append(items, types.ListEntryItem{
+					Index: len(items),
+					Value: strutil.EncodeRedisKey(val),
+				})
+				if doConvert {
+					if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
+						items[len(items)-1].DisplayValue = dv
+					}
+				}
+			}
+			if subErr != nil {
+				return items, reset, false, subErr
+			}
+			return items, reset, cursor == 0, nil
+		}
+
+		data.Value, data.Reset, data.End, err = loadListHandle()
+		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+	case "hash":
+		if !strings.HasPrefix(matchPattern, "*") {
+			matchPattern = "*" + matchPattern
+		}
+		if !strings.HasSuffix(matchPattern, "*") {
+			matchPattern = matchPattern + "*"
+		}
+		loadHashHandle := func() ([]types.HashEntryItem, bool, bool, error) {
+			var items []types.HashEntryItem
+			var loadedVal []string
+			var cursor uint64
+			var reset bool
+			var subErr error
+			scanSize := int64(Preferences().GetScanSize())
+			if param.Full || matchPattern != "*" {
+				// load all
+				cursor, reset = 0, true
+				items = []types.HashEntryItem{}
+				for {
+					loadedVal, cursor, subErr = client.HScan(ctx, key, cursor, matchPattern, scanSize).Result()
+					if subErr != nil {
+						return nil, reset, false, subErr
+					}
+					for i := 0; i < len(loadedVal); i += 2 {
+						items = append(items, types.HashEntryItem{
+							Key:   loadedVal[i],
+							Value: strutil.EncodeRedisKey(loadedVal[i+1]),
+						})
+						if doConvert {
+							if dv, _, _ := convutil.ConvertTo(loadedVal[i+1], param.Decode, param.Format, decoder); dv != loadedVal[i+1] {
+								items[len(items)-1].DisplayValue = dv
+							}
+						}
+					}
+					if cursor == 0 {
+						break
+					}
+				}
+			} else {
+				if param.Reset {
+					cursor, reset = 0, true
+				} else {
+					cursor, _, reset = getEntryCursor()
+				}
+				loadedVal, cursor, subErr = client.HScan(ctx, key, cursor, matchPattern, scanSize).Result()
+				if subErr != nil {
+					return nil, reset, false, subErr
+				}
+				loadedLen := len(loadedVal)
+				items = make([]types.HashEntryItem, loadedLen/2)
+				for i := 0; i < loadedLen; i += 2 {
+					items[i/2].Key = loadedVal[i]
+					items[i/2].Value = strutil.EncodeRedisKey(loadedVal[i+1])
+					if doConvert {
+						if dv, _, _ := convutil.ConvertTo(loadedVal[i+1], param.Decode, param.Format, decoder); dv != loadedVal[i+1] {
+							items[i/2].DisplayValue = dv
+						}
+					}
+				}
+			}
+			setEntryCursor(cursor)
+			return items, reset, cursor == 0, nil
+		}
+
+		data.Value, data.Reset, data.End, err = loadHashHandle()
+		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+	case "set":
+		if !strings.HasPrefix(matchPattern, "*") {
+			matchPattern = "*" + matchPattern
+		}
+		if !strings.HasSuffix(matchPattern, "*") {
+			matchPattern = matchPattern + "*"
+		}
+		loadSetHandle := func() ([]types.SetEntryItem, bool, bool, error) {
+			var items []types.SetEntryItem
+			var cursor uint64
+			var reset bool
+			var subErr error
+			var loadedKey []string
+			scanSize := int64(Preferences().GetScanSize())
+			if param.Full || matchPattern != "*" {
+				// load all
+				cursor, reset = 0, true
+				items = []types.SetEntryItem{}
+				for {
+					loadedKey, cursor, subErr = client.SScan(ctx, key, cursor, matchPattern, scanSize).Result()
+					if subErr != nil {
+						return items, reset, false, subErr
+					}
+					for _, val := range loadedKey {
+						items = append(items, types.SetEntryItem{
+							Value: strutil.EncodeRedisKey(val),
+						})
+						if doConvert {
+							if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
+								items[len(items)-1].DisplayValue = dv
+							}
+						}
+					}
+					if cursor == 0 {
+						break
+					}
+				}
+			} else {
+				if param.Reset {
+					cursor, reset = 0, true
+				} else {
+					cursor, _, reset = getEntryCursor()
+				}
+				loadedKey, cursor, subErr = client.SScan(ctx, key, cursor, matchPattern, scanSize).Result()
+				items = make([]types.SetEntryItem, len(loadedKey))
+				for i, val := range loadedKey {
+					items[i].Value = strutil.EncodeRedisKey(val)
+					if doConvert {
+						if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
+							items[i].DisplayValue = dv
+						}
+					}
+				}
+			}
+			setEntryCursor(cursor)
+			return items, reset, cursor == 0, nil
+		}
+
+		data.Value, data.Reset, data.End, err = loadSetHandle()
+		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+	case "zset":
+		if !strings.HasPrefix(matchPattern, "*") {
+			matchPattern = "*" + matchPattern
+		}
+		if !strings.HasSuffix(matchPattern, "*") {
+			matchPattern = matchPattern + "*"
+		}
+		loadZSetHandle := func() ([]types.ZSetEntryItem, bool, bool, error) {
+			var items []types.ZSetEntryItem
+			var reset bool
+			var cursor uint64
+			scanSize := int64(Preferences().GetScanSize())
+			doFilter := matchPattern != "*"
+			if param.Full || doFilter {
+				// load all
+				var loadedVal []string
+				cursor, reset = 0, true
+				items = []types.ZSetEntryItem{}
+				for {
+					loadedVal, cursor, err = client.ZScan(ctx, key, cursor, matchPattern, scanSize).Result()
+					if err != nil {
+						return items, reset, false, err
+					}
+					var score float64
+					for i := 0; i < len(loadedVal); i += 2 {
+						if score, err = strconv.ParseFloat(loadedVal[i+1], 64); err == nil {
+							items = append(items, types.ZSetEntryItem{
+								Value: strutil.EncodeRedisKey(loadedVal[i]),
+								Score: score,
+							})
+							if doConvert {
+								if dv, _, _ := convutil.ConvertTo(loadedVal[i], param.Decode, param.Format, decoder); dv != loadedVal[i] {
+									items[len(items)-1].DisplayValue = dv
+								}
+							}
+						}
+					}
+					if cursor == 0 {
+						break
+					}
+				}
+			} else {
+				if param.Reset {
+					cursor, reset = 0, true
+				} else {
+					cursor, _, reset = getEntryCursor()
+				}
+				var loadedVal []redis.Z
+				loadedVal, err = client.ZRangeWithScores(ctx, key, int64(cursor), int64(cursor)+scanSize-1).Result()
+				cursor = cursor + uint64(scanSize)
+				if len(loadedVal) < int(scanSize) {
+					cursor = 0
+				}
+
+				items = make([]types.ZSetEntryItem, 0, len(loadedVal))
+				for _, z := range loadedVal {
+					val := strutil.AnyToString(z.Member, "", 0)
+					if doFilter && !strings.Contains(val, param.MatchPattern) {
+						continue
+					}
+					entry := types.ZSetEntryItem{
+						Value: strutil.EncodeRedisKey(val),
+					}
+					if math.IsInf(z.Score, 1) {
+						entry.ScoreStr = "+inf"
+					} else if math.IsInf(z.Score, -1) {
+						entry.ScoreStr = "-inf"
+					} else {
+						entry.Score = z.Score
+					}
+					items = append(items, entry)
+					if doConvert {
+						if dv, _, _ := convutil.ConvertTo(val, param.Decode, param.Format, decoder); dv != val {
+							items[len(items)-1].DisplayValue = dv
+						}
+					}
+				}
+			}
+			setEntryCursor(cursor)
+			return items, reset, cursor == 0, nil
+		}
+
+		data.Value, data.Reset, data.End, err = loadZSetHandle()
+		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+	case "stream":
+		loadStreamHandle := func() ([]types.StreamEntryItem, bool, bool, error) {
+			var msgs []redis.XMessage
+			var last string
+			var reset bool
+			doFilter := matchPattern != "*"
+			if param.Full || doFilter {
+				// load all
+				last, reset = "", true
+				msgs, err = client.XRevRange(ctx, key, "+", "-").Result()
+			} else {
+				scanSize := int64(Preferences().GetScanSize())
+				if param.Reset {
+					last = ""
+				} else {
+					_, last, reset = getEntryCursor()
+				}
+				if len(last) <= 0 {
+					last = "+"
+				}
+				if last != "+" {
+					// add 1 more item when continue scan
+					msgs, err = client.XRevRangeN(ctx, key, last, "-", scanSize+1).Result()
+					msgs = msgs[1:]
+				} else {
+					msgs, err = client.XRevRangeN(ctx, key, last, "-", scanSize).Result()
+				}
+				scanCount := len(msgs)
+				if scanCount <= 0 || scanCount < int(scanSize) {
+					last = ""
+				} else if scanCount > 0 {
+					last = msgs[scanCount-1].ID
+				}
+			}
+			setEntryXLast(last)
+			items := make([]types.StreamEntryItem, 0, len(msgs))
+			for _, msg := range msgs {
+				it := types.StreamEntryItem{
+					ID:    msg.ID,
+					Value: msg.Values,
+				}
+				if vb, merr := json.Marshal(msg.Values); merr != nil {
+					it.DisplayValue = "{}"
+				} else {
+					it.DisplayValue, _, _ = convutil.ConvertTo(string(vb), types.DECODE_NONE, types.FORMAT_JSON, decoder)
+				}
+				if doFilter && !strings.Contains(it.DisplayValue, param.MatchPattern) {
+					continue
+				}
+				items = append(items, it)
+			}
+			if err != nil {
+				return items, reset, false, err
+			}
+			return items, reset, last == "", nil
+		}
+
+		data.Value, data.Reset, data.End, err = loadStreamHandle()
+		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+	case "rejson-rl":
+		var jsonStr string
+		data.KeyType = "JSON"
+		jsonStr, err = client.JSONGet(ctx, key).Result()
+		data.Value, data.Decode, data.Format = convutil.ConvertTo(jsonStr, types.DECODE_NONE, types.FORMAT_JSON, nil)
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Success = true
+	resp.Data = data
+	return
+}
+
+// ConvertValue convert value with decode method and format
+// blank decode indicate auto decode
+// blank format indicate auto format
+func (b *browserService) ConvertValue(value any, decode, format string) (resp types.JSResp) {
+	str := strutil.DecodeRedisKey(value)
+	value, decode, format = convutil.ConvertTo(str, decode, format, Preferences().GetDecoder())
+	resp.Success = true
+	resp.Data = map[string]any{
+		"value":  value,
+		"decode": decode,
+		"format": format,
+	}
+	return
+}
+
+// SetKeyValue set value by key
+// @param ttl <= 0 means keep current ttl
+func (b *browserService) SetKeyValue(param types.SetKeyParam) (resp types.JSResp) {
+	defer Audit().record(param.Server, param.DB, "set", strutil.DecodeRedisKey(param.Key), "", &resp)
+
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	ctx := item.ctx
+	key := strutil.DecodeRedisKey(param.Key)
+	var client redis.Cmdable = item.client
+	var conn *redis.Conn
+	if param.WaitReplicas > 0 {
+		// write on dedicated connection, WAIT only counts acknowledges of writes on the same connection
+		if conn, err = b.dedicatedConn(ctx, param.Server, item.client, key); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		defer conn.Close()
+		client = conn
+	}
+	var expiration time.Duration
+	if param.TTL < 0 {
+		if expiration, err = client.PTTL(ctx, key).Result(); err != nil {
+			expiration = redis.KeepTTL
+		}
+	} else {
+		expiration = time.Duration(param.TTL) * time.Second
+	}
+	// use default decode type and format
+	if len(param.Decode) <= 0 {
+		param.Decode = types.DECODE_NONE
+	}
+	if len(param.Format) <= 0 {
+		param.Format = types.FORMAT_RAW
+	}
+	var savedValue any
+	switch strings.ToLower(param.KeyType) {
+	case "string":
+		if str, ok := param.Value.(string); !ok {
+			resp.Msg = "invalid string value"
+			return
+		} else {
+			if savedValue, err = convutil.SaveAs(str, param.Format, param.Decode, Preferences().GetDecoder()); err != nil {
+				resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
+				return
+			}
+			_, err = client.Set(ctx, key, savedValue, 0).Result()
+			// set expiration lonely, not "keepttl"
+			if err == nil && expiration > 0 {
+				client.Expire(ctx, key, expiration)
+			}
+		}
+	case "list":
+		if strs, ok := param.Value.([]any); !ok {
+			resp.Msg = "invalid list value"
+			return
+		} else {
+			err = client.LPush(ctx, key, strs...).Err()
+			if err == nil && expiration > 0 {
+				client.Expire(ctx, key, expiration)
+			}
+		}
+	case "hash":
+		if strs, ok := param.Value.([]any); !ok {
+			resp.Msg = "invalid hash value"
+			return
+		} else {
+			total := len(strs)
+			if total > 1 {
+				_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+					for i := 0; i < total; i += 2 {
+						pipe.HSet(ctx, key, strs[i], strs[i+1])
+					}
+					if expiration > 0 {
+						pipe.Expire(ctx, key, expiration)
+					}
+					return nil
+				})
+			}
+		}
+	case "set":
+		if strs, ok := param.Value.([]any); !ok || len(strs) <= 0 {
+			resp.Msg = "invalid set value"
+			return
+		} else {
+			if len(strs) > 0 {
+				err = client.SAdd(ctx, key, strs...).Err()
+				if err == nil && expiration > 0 {
+					client.Expire(ctx, key, expiration)
+				}
+			}
+		}
+	case "zset":
+		if strs, ok := param.Value.([]any); !ok || len(strs) <= 0 {
+			resp.Msg = "invalid zset value"
+			return
+		} else {
+			if len(strs) > 1 {
+				var members []redis.Z
+				for i := 0; i < len(strs); i += 2 {
+					score, _ := strconv.ParseFloat(strs[i+1].(string), 64)
+					members = append(members, redis.Z{
+						Score:  score,
+						Member: strs[i],
+					})
+				}
+				err = client.ZAdd(ctx, key, members...).Err()
+				if err == nil && expiration > 0 {
+					client.Expire(ctx, key, expiration)
+				}
+			}
+		}
+	case "stream":
+		if strs, ok := param.Value.([]any); !ok {
+			resp.Msg = "invalid stream value"
+			return
+		} else {
+			if len(strs) > 2 {
+				err = client.XAdd(ctx, &redis.XAddArgs{
+					Stream: key,
+					ID:     strs[0].(string),
+					Values: strs[1:],
+				}).Err()
+				if err == nil && expiration > 0 {
+					client.Expire(ctx, key, expiration)
+				}
+			}
+		}
+	case "json":
+		err = client.JSONSet(ctx, key, ".", param.Value).Err()
+		if err == nil && expiration > 0 {
+			client.Expire(ctx, key, expiration)
+		}
+		var ok bool
+		if savedValue, ok = param.Value.(string); !ok {
+			savedValue = ""
+		}
+	}
+
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Success = true
+	respData := map[string]any{}
+	if val, ok := savedValue.(string); ok {
+		respData["value"] = strutil.EncodeRedisKey(val)
+	}
+	if conn != nil {
+		respData["acked"], respData["warning"] = b.waitReplicas(ctx, conn, param.WaitReplicas, param.WaitTimeout)
+	}
+	resp.Data = respData
+	return
+}
+
+// get a dedicated connection to the master node which key belongs to
+// hooks of client are not inherited by the connection, so read-only hook is added again if required
+func (b *browserService) dedicatedConn(ctx context.Context, server string, client redis.UniversalClient, key string) (*redis.Conn, error) {
+	var conn *redis.Conn
+	switch cli := client.(type) {
+	case *redis.ClusterClient:
+		node, err := cli.MasterForKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		conn = node.Conn()
+	case *redis.Client:
+		conn = cli.Conn()
+	default:
+		return nil, errors.New("unsupported client type")
+	}
+	if conf := Connection().getConnection(server); conf == nil || conf.ReadOnly {
+		conn.AddHook(redis2.NewReadOnlyHook())
+	}
+	return conn, nil
+}
+
+// wait writes on connection acknowledged by replicas via WAIT, returns count of acknowledged replicas
+// and warning if fewer than requested acknowledged within timeout
+func (b *browserService) waitReplicas(ctx context.Context, conn *redis.Conn, numReplicas int, timeoutMs int64) (int64, string) {
+	if timeoutMs <= 0 {
+		timeoutMs = consts.DEFAULT_WAIT_TIMEOUT
+	}
+	acked, err := conn.Wait(ctx, numReplicas, time.Duration(timeoutMs)*time.Millisecond).Result()
+	if err != nil {
+		return 0, "wait for replicas fail: " + err.Error()
+	}
+	if acked < int64(numReplicas) {
+		return acked, fmt.Sprintf("only %d of %d replicas acknowledged the write within %dms", acked, numReplicas, timeoutMs)
+	}
+	return acked, ""
+}
+
+// get length of key by its type
+func (b *browserService) getKeyLength(ctx context.Context, client redis.UniversalClient, key, keyType string) (int64, error) {
+	switch keyType {
+	case "string":
+		return client.StrLen(ctx, key).Result()
+	case "list":
+		return client.LLen(ctx, key).Result()
+	case "hash":
+		return client.HLen(ctx, key).Result()
+	case "set":
+		return client.SCard(ctx, key).Result()
+	case "zset":
+		return client.ZCard(ctx, key).Result()
+	case "stream":
+		return client.XLen(ctx, key).Result()
+	}
+	return 0, nil
+}
+
+// CreateKey create a new key with initial value by type
+// @param keyType string/list/hash/set/zset/stream
+// @param initialValue string for "string", array of elements for "list" and "set",
+// object of field and value for "hash" and "stream", object of member and score for "zset"
+// @param ttl expiration in seconds, never expire if <= 0
+// @param overwrite replace existing key if true, otherwise fail if key exists
+func (b *browserService) CreateKey(server string, db int, k any, keyType string, initialValue any, ttl int64, overwrite bool) (resp types.JSResp) {
+	defer Audit().record(server, db, "create", strutil.DecodeRedisKey(k), keyType, &resp)
+	keyType = strings.ToLower(keyType)
+	toMap := func() (map[string]any, bool) {
+		m, ok := initialValue.(map[string]any)
+		return m, ok && len(m) > 0
+	}
+	toList := func() ([]any, bool) {
+		l, ok := initialValue.([]any)
+		return l, ok && len(l) > 0
+	}
+
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	if !overwrite {
+		if n, err := client.Exists(ctx, key).Result(); err != nil {
+			resp.Msg = err.Error()
+			return
+		} else if n > 0 {
+			resp.Msg = "key already exists"
+			return
+		}
+	}
+
+	var create func(pipe redis.Pipeliner)
+	switch keyType {
+	case "string":
+		str, ok := initialValue.(string)
+		if !ok {
+			resp.Msg = "invalid string value"
+			return
+		}
+		create = func(pipe redis.Pipeliner) {
+			pipe.Set(ctx, key, str, 0)
+		}
+	case "list":
+		elems, ok := toList()
+		if !ok {
+			resp.Msg = "invalid list value"
+			return
+		}
+		create = func(pipe redis.Pipeliner) {
+			pipe.RPush(ctx, key, elems...)
+		}
+	case "set":
+		elems, ok := toList()
+		if !ok {
+			resp.Msg = "invalid set value"
+			return
+		}
+		create = func(pipe redis.Pipeliner) {
+			pipe.SAdd(ctx, key, elems...)
+		}
+	case "hash":
+		fields, ok := toMap()
+		if !ok {
+			resp.Msg = "invalid hash value"
+			return
+		}
+		create = func(pipe redis.Pipeliner) {
+			pipe.HSet(ctx, key, fields)
+		}
+	case "zset":
+		scores, ok := toMap()
+		if !ok {
+			resp.Msg = "invalid zset value"
+			return
+		}
+		members := make([]redis.Z, 0, len(scores))
+		for member, s := range scores {
+			score, ok := s.(float64)
+			if !ok {
+				if score, err = strconv.ParseFloat(strutil.AnyToString(s, "", 0), 64); err != nil {
+					resp.Msg = fmt.Sprintf("invalid score of member \"%s\"", member)
+					return
+				}
+			}
+			members = append(members, redis.Z{Score: score, Member: member})
+		}
+		create = func(pipe redis.Pipeliner) {
+			pipe.ZAdd(ctx, key, members...)
+		}
+	case "stream":
+		fields, ok := toMap()
+		if !ok {
+			resp.Msg = "invalid stream value"
+			return
+		}
+		create = func(pipe redis.Pipeliner) {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: key,
+				ID:     "*",
+				Values: fields,
+			})
+		}
+	default:
+		resp.Msg = "unsupported key type: " + keyType
+		return
+	}
+
+	// overwrite and create in one transaction
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if overwrite {
+			pipe.Del(ctx, key)
+		}
+		create(pipe)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, time.Duration(ttl)*time.Second)
+		}
+		return nil
+	})
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	length, _ := b.getKeyLength(ctx, client, key, keyType)
+	resp.Success = true
+	resp.Data = struct {
+		Type   string `json:"type"`
+		Length int64  `json:"length"`
+	}{
+		Type:   keyType,
+		Length: length,
+	}
+	return
+}
+
+// ConvertType convert collection key to another type, the original TTL is preserved
+// converted value is written to a temporary key first, then replace the original one by RENAME
+// @param targetType list/set/zset
+func (b *browserService) ConvertType(server string, db int, k any, targetType string) (resp types.JSResp) {
+	defer Audit().record(server, db, "convert", strutil.DecodeRedisKey(k), "to "+targetType, &resp)
+	targetType = strings.ToLower(targetType)
+	switch targetType {
+	case "list", "set", "zset":
+	default:
+		resp.Msg = "unsupported target type: " + targetType
+		return
+	}
+
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	srcType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if srcType == "none" {
+		resp.Msg = "key not exists"
+		return
+	}
+	if srcType == targetType {
+		resp.Msg = "key is already of type " + targetType
+		return
+	}
+
+	// read all elements in order, scores are kept for zset only
+	var elems []string
+	var scores []float64
+	var warnings []string
+	switch srcType {
+	case "list":
+		elems, err = client.LRange(ctx, key, 0, -1).Result()
+	case "set":
+		elems, err = client.SMembers(ctx, key).Result()
+		if targetType == "list" {
+			warnings = append(warnings, "members of set are unordered, the order of list is arbitrary")
+		}
+	case "zset":
+		var zs []redis.Z
+		if zs, err = client.ZRangeWithScores(ctx, key, 0, -1).Result(); err == nil {
+			for _, z := range zs {
+				elems = append(elems, strutil.AnyToString(z.Member, "", 0))
+				scores = append(scores, z.Score)
+			}
+		}
+		warnings = append(warnings, "scores of members are dropped")
+	default:
+		resp.Msg = fmt.Sprintf("can not convert key of type %s", srcType)
+		return
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	ttl, err := client.PTTL(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	if srcType == "list" && targetType != "list" {
+		if uniq := sliceutil.Unique(elems); len(uniq) < len(elems) {
+			warnings = append(warnings, fmt.Sprintf("%d duplicated elements are dropped", len(elems)-len(uniq)))
+			elems = uniq
+		}
+		if targetType == "zset" {
+			warnings = append(warnings, "index of element in list is used as score")
+		}
+	} else if srcType == "set" && targetType == "zset" {
+		warnings = append(warnings, "all scores are set to 0")
+	}
+
+	// temporary key should be in the same slot in cluster mode, wrap the hashed part of key as its hash tag
+	tag := redis2.HashTag(key)
+	if strings.Contains(tag, "}") {
+		// whole key is hashed but can not be wrapped, find another tag of the same slot
+		// numbers below 110000 cover all slots
+		for i, slot := 0, redis2.HashSlot(key); ; i++ {
+			if t := strconv.Itoa(i); redis2.HashSlot(t) == slot {
+				tag = t
+				break
+			}
+		}
+	}
+	tmpKey := fmt.Sprintf("{%s}:tinyrdm:convert:%d", tag, time.Now().UnixNano())
+
+	args := sliceutil.Map(elems, func(i int) any {
+		return elems[i]
+	})
+	_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		// write in batches to avoid too large command
+		const batchSize = 1000
+		for start := 0; start < len(args); start += batchSize {
+			end := min(start+batchSize, len(args))
+			switch targetType {
+			case "list":
+				pipe.RPush(ctx, tmpKey, args[start:end]...)
+			case "set":
+				pipe.SAdd(ctx, tmpKey, args[start:end]...)
+			case "zset":
+				members := make([]redis.Z, 0, end-start)
+				for i := start; i < end; i++ {
+					var score float64
+					if srcType == "list" {
+						score = float64(i)
+					}
+					members = append(members, redis.Z{Score: score, Member: args[i]})
+				}
+				pipe.ZAdd(ctx, tmpKey, members...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		client.Del(ctx, tmpKey)
+		resp.Msg = err.Error()
+		return
+	}
+
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Rename(ctx, tmpKey, key)
+		if ttl > 0 {
+			pipe.PExpire(ctx, key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		client.Del(ctx, tmpKey)
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Type     string   `json:"type"`
+		Length   int      `json:"length"`
+		Warnings []string `json:"warnings,omitempty"` // lossy changes during converting
+	}{
+		Type:     targetType,
+		Length:   len(elems),
+		Warnings: warnings,
+	}
+	return
+}
+
+// GetHashValue get hash field
+func (b *browserService) GetHashValue(param types.GetHashParam) (resp types.JSResp) {
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(param.Key)
+	val, err := client.HGet(ctx, key, param.Field).Result()
+	if errors.Is(err, redis.Nil) {
+		resp.Msg = "field in key not found"
+		return
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	var displayVal string
+	if (len(param.Decode) > 0 && param.Decode != types.DECODE_NONE) ||
+		(len(param.Format) > 0 && param.Format != types.FORMAT_RAW) {
+		decoder := Preferences().GetDecoder()
+		displayVal, _, _ = convutil.ConvertTo(val, param.Decode, param.Format, decoder)
+		if displayVal == val {
+			displayVal = ""
+		}
+	}
+
+	resp.Data = types.HashEntryItem{
+		Key:          param.Field,
+		Value:        val,
+		DisplayValue: displayVal,
+	}
+	resp.Success = true
+	return
+}
+
+// SetHashValue update hash field
+func (b *browserService) SetHashValue(param types.SetHashParam) (resp types.JSResp) {
+	defer Audit().record(param.Server, param.DB, "set_hash", strutil.DecodeRedisKey(param.Key), "field "+param.Field, &resp)
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(param.Key)
+	str := strutil.DecodeRedisKey(param.Value)
+	var saveStr, displayStr string
+	decoder := Preferences().GetDecoder()
+	if saveStr, err = convutil.SaveAs(str, param.Format, param.Decode, decoder); err != nil {
+		resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
+		return
+	}
+	if len(param.RetDecode) > 0 && len(param.RetFormat) > 0 {
+		displayStr, _, _ = convutil.ConvertTo(saveStr, param.RetDecode, param.RetFormat, decoder)
+	}
+	var updated, added, removed []types.HashEntryItem
+	var replaced []types.HashReplaceItem
+	var affect int64
+	if len(param.NewField) <= 0 {
+		// new field is empty, delete old field
+		_, err = client.HDel(ctx, key, param.Field).Result()
+		removed = append(removed, types.HashEntryItem{
+			Key: param.Field,
+		})
+	} else if len(param.Field) <= 0 || param.Field == param.NewField {
+		affect, err = client.HSet(ctx, key, param.NewField, saveStr).Result()
+		if affect <= 0 {
+			// update field value
+			updated = append(updated, types.HashEntryItem{
+				Key:          param.NewField,
+				Value:        saveStr,
+				DisplayValue: displayStr,
+			})
+		} else {
+			// add new field
+			added = append(added, types.HashEntryItem{
+				Key:          param.NewField,
+				Value:        saveStr,
+				DisplayValue: displayStr,
+			})
+		}
+	} else {
+		// remove old field and add new field
+		if _, err = client.HDel(ctx, key, param.Field).Result(); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+		affect, err = client.HSet(ctx, key, param.NewField, saveStr).Result()
+		if affect <= 0 {
+			// no new filed added, just update exists item
+			removed = append(removed, types.HashEntryItem{
+				Key: param.Field,
+			})
+			updated = append(updated, types.HashEntryItem{
+				Key:          param.NewField,
+				Value:        saveStr,
+				DisplayValue: displayStr,
+			})
+		} else {
+			// add new field
+			replaced = append(replaced, types.HashReplaceItem{
+				Key:          param.Field,
+				NewKey:       param.NewField,
+				Value:        saveStr,
+				DisplayValue: displayStr,
+			})
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added    []types.HashEntryItem   `json:"added,omitempty"`
+		Removed  []types.HashEntryItem   `json:"removed,omitempty"`
+		Updated  []types.HashEntryItem   `json:"updated,omitempty"`
+		Replaced []types.HashReplaceItem `json:"replaced,omitempty"`
+	}{
+		Added:    added,
+		Removed:  removed,
+		Updated:  updated,
+		Replaced: replaced,
+	}
+	return
+}
+
+// AddHashField add or update hash field
+func (b *browserService) AddHashField(server string, db int, k any, action int, fieldItems []any) (resp types.JSResp) {
+	defer Audit().record(server, db, "add_hash", strutil.DecodeRedisKey(k), fmt.Sprintf("%d fields", len(fieldItems)/2), &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	var updated []types.HashEntryItem
+	var added []types.HashEntryItem
+	switch action {
+	case 1:
+		// ignore duplicated fields
+		for i := 0; i < len(fieldItems); i += 2 {
+			field, value := strutil.DecodeRedisKey(fieldItems[i]), strutil.DecodeRedisKey(fieldItems[i+1])
+			if succ, _ := client.HSetNX(ctx, key, field, value).Result(); succ {
+				added = append(added, types.HashEntryItem{
+					Key:          field,
+					Value:        value,
+					DisplayValue: "", // TODO: convert to display value
+				})
+			}
+		}
+	default:
+		// overwrite duplicated fields
+		total := len(fieldItems)
+		if total > 1 {
+			for i := 0; i < total; i += 2 {
+				field, value := strutil.DecodeRedisKey(fieldItems[i]), strutil.DecodeRedisKey(fieldItems[i+1])
+				if affect, _ := client.HSet(ctx, key, field, value).Result(); affect > 0 {
+					added = append(added, types.HashEntryItem{
+						Key:          field,
+						Value:        value,
+						DisplayValue: "", // TODO: convert to display value
+					})
+				} else {
+					updated = append(updated, types.HashEntryItem{
+						Key:          field,
+						Value:        value,
+						DisplayValue: "", // TODO: convert to display value
+					})
+				}
+			}
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added   []types.HashEntryItem `json:"added,omitempty"`
+		Updated []types.HashEntryItem `json:"updated,omitempty"`
+	}{
+		Added:   added,
+		Updated: updated,
+	}
+	return
+}
+
+// ScanHashFields scan one page of hash fields by HSCAN, start with cursor 0
+// total fields count(HLEN) is attached in the first page
+func (b *browserService) ScanHashFields(server string, db int, k any, pattern string, cursor uint64, count int64) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	if len(pattern) <= 0 {
+		pattern = "*"
+	}
+	if count <= 0 {
+		count = int64(Preferences().GetScanSize())
+	}
+
+	var total int64 = -1
+	if cursor == 0 {
+		if total, err = client.HLen(ctx, key).Result(); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+	}
+	loadedVal, nextCursor, err := client.HScan(ctx, key, cursor, pattern, count).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	fields := make([]types.HashEntryItem, 0, len(loadedVal)/2)
+	for i := 0; i+1 < len(loadedVal); i += 2 {
+		fields = append(fields, types.HashEntryItem{
+			Key:   loadedVal[i],
+			Value: strutil.EncodeRedisKey(loadedVal[i+1]),
+		})
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Fields []types.HashEntryItem `json:"fields"`
+		Cursor uint64                `json:"cursor"`
+		End    bool                  `json:"end"`
+		Total  int64                 `json:"total,omitempty"` // only present in the first page
+	}{
+		Fields: fields,
+		Cursor: nextCursor,
+		End:    nextCursor == 0,
+		Total:  max(total, 0),
+	}
+	return
+}
+
+// SetHashField set value of one hash field
+func (b *browserService) SetHashField(server string, db int, k any, field string, value any) (resp types.JSResp) {
+	defer Audit().record(server, db, "set_hash", strutil.DecodeRedisKey(k), "field "+field, &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	added, err := client.HSet(ctx, key, field, strutil.DecodeRedisKey(value)).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added bool `json:"added"` // false if updated existing field
+	}{
+		Added: added > 0,
+	}
+	return
+}
+
+// DeleteHashField delete one hash field
+func (b *browserService) DeleteHashField(server string, db int, k any, field string) (resp types.JSResp) {
+	defer Audit().record(server, db, "delete_hash", strutil.DecodeRedisKey(k), "field "+field, &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	removed, err := client.HDel(ctx, key, field).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if removed <= 0 {
+		resp.Msg = "field not exists"
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// AddListItem add item to list or remove from it
+func (b *browserService) AddListItem(server string, db int, k any, action int, items []any) (resp types.JSResp) {
+	defer Audit().record(server, db, "add_list", strutil.DecodeRedisKey(k), fmt.Sprintf("%d items", len(items)), &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	var leftPush, rightPush []types.ListEntryItem
+	switch action {
+	case 0:
+		// push to head
+		slices.Reverse(items)
+		_, err = client.LPush(ctx, key, items...).Result()
+		for i := len(items) - 1; i >= 0; i-- {
+			leftPush = append(leftPush, types.ListEntryItem{
+				Value:        items[i],
+				DisplayValue: "", // TODO: convert to display value
+			})
+		}
+	default:
+		// append to tail
+		_, err = client.RPush(ctx, key, items...).Result()
+		for _, it := range items {
+			rightPush = append(rightPush, types.ListEntryItem{
+				Value:        it,
+				DisplayValue: "", // TODO: convert to display value
+			})
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Left  []types.ListEntryItem `json:"left,omitempty"`
+		Right []types.ListEntryItem `json:"right,omitempty"`
+	}{
+		Left:  leftPush,
+		Right: rightPush,
+	}
+	return
+}
+
+// SetListItem update or remove list item by index
+func (b *browserService) SetListItem(param types.SetListParam) (resp types.JSResp) {
+	defer Audit().record(param.Server, param.DB, "set_list", strutil.DecodeRedisKey(param.Key), fmt.Sprintf("index %d", param.Index), &resp)
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(param.Key)
+	str := strutil.DecodeRedisKey(param.Value)
+	index := int64(param.Index)
+	var replaced, removed []types.ListReplaceItem
+	if len(str) <= 0 {
+		// remove from list
+		err = client.LSet(ctx, key, index, "---VALUE_REMOVED_BY_TINY_RDM---").Err()
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+
+		err = client.LRem(ctx, key, 1, "---VALUE_REMOVED_BY_TINY_RDM---").Err()
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		removed = append(removed, types.ListReplaceItem{
+			Index: param.Index,
+		})
+	} else {
+		// replace index value
+		var saveStr string
+		decoder := Preferences().GetDecoder()
+		if saveStr, err = convutil.SaveAs(str, param.Format, param.Decode, decoder); err != nil {
+			resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
+			return
+		}
+		err = client.LSet(ctx, key, index, saveStr).Err()
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		var displayStr string
+		if len(param.RetDecode) > 0 && len(param.RetFormat) > 0 {
+			displayStr, _, _ = convutil.ConvertTo(saveStr, param.RetDecode, param.RetFormat, decoder)
+		}
+		replaced = append(replaced, types.ListReplaceItem{
+			Index:        param.Index,
+			Value:        saveStr,
+			DisplayValue: displayStr,
+		})
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Removed  []types.ListReplaceItem `json:"removed,omitempty"`
+		Replaced []types.ListReplaceItem `json:"replaced,omitempty"`
+	}{
+		Removed:  removed,
+		Replaced: replaced,
+	}
+	return
+}
+
+// SetSetItem add members to set or remove from set
+func (b *browserService) SetSetItem(server string, db int, k any, remove bool, members []any) (resp types.JSResp) {
+	defer Audit().record(server, db, "set_set", strutil.DecodeRedisKey(k), fmt.Sprintf("%d members, remove: %t", len(members), remove), &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	var added, removed []types.SetEntryItem
+	var affected int64
+	if remove {
+		for _, member := range members {
+			if affected, _ = client.SRem(ctx, key, member).Result(); affected > 0 {
+				removed = append(removed, types.SetEntryItem{
+					Value: member,
+				})
+			}
+		}
+	} else {
+		for _, member := range members {
+			if affected, _ = client.SAdd(ctx, key, member).Result(); affected > 0 {
+				added = append(added, types.SetEntryItem{
+					Value:        member,
+					DisplayValue: "", // TODO: convert to display value
+				})
+			}
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added    []types.SetEntryItem `json:"added,omitempty"`
+		Removed  []types.SetEntryItem `json:"removed,omitempty"`
+		Affected int64                `json:"affected"`
+	}{
+		Added:    added,
+		Removed:  removed,
+		Affected: affected,
+	}
+	return
+}
+
+// UpdateSetItem replace member of set
+func (b *browserService) UpdateSetItem(param types.SetSetParam) (resp types.JSResp) {
+	defer Audit().record(param.Server, param.DB, "set_set", strutil.DecodeRedisKey(param.Key), "", &resp)
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(param.Key)
+	var added, removed []types.SetEntryItem
+	var affect int64
+	// remove old value
+	str := strutil.DecodeRedisKey(param.Value)
+	if affect, _ = client.SRem(ctx, key, str).Result(); affect > 0 {
+		removed = append(removed, types.SetEntryItem{
+			Value: str,
+		})
+	}
+
+	// insert new value
+	str = strutil.DecodeRedisKey(param.NewValue)
+	decoder := Preferences().GetDecoder()
+	var saveStr string
+	if saveStr, err = convutil.SaveAs(str, param.Format, param.Decode, decoder); err != nil {
+		resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
+		return
+	}
+	if affect, _ = client.SAdd(ctx, key, saveStr).Result(); affect > 0 {
+		// add new item
+		var displayStr string
+		if len(param.RetDecode) > 0 && len(param.RetFormat) > 0 {
+			displayStr, _, _ = convutil.ConvertTo(saveStr, param.RetDecode, param.RetFormat, decoder)
+		}
+		added = append(added, types.SetEntryItem{
+			Value:        saveStr,
+			DisplayValue: displayStr,
+		})
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added   []types.SetEntryItem `json:"added,omitempty"`
+		Removed []types.SetEntryItem `json:"removed,omitempty"`
+	}{
+		Added:   added,
+		Removed: removed,
+	}
+	return
+}
+
+// UpdateZSetValue update value of sorted set member
+func (b *browserService) UpdateZSetValue(param types.SetZSetParam) (resp types.JSResp) {
+	defer Audit().record(param.Server, param.DB, "set_zset", strutil.DecodeRedisKey(param.Key), "", &resp)
+	item, err := b.getRedisClient(param.Server, param.DB)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(param.Key)
+	val, newVal := strutil.DecodeRedisKey(param.Value), strutil.DecodeRedisKey(param.NewValue)
+	var added, updated, removed []types.ZSetEntryItem
+	var replaced []types.ZSetReplaceItem
+	var affect int64
+	decoder := Preferences().GetDecoder()
+	if len(newVal) <= 0 {
+		// no new value, delete value
+		if affect, err = client.ZRem(ctx, key, val).Result(); affect > 0 {
+			//removed = append(removed, val)
+			removed = append(removed, types.ZSetEntryItem{
+				Value: val,
+			})
+		}
+	} else {
+		var saveVal string
+		if saveVal, err = convutil.SaveAs(newVal, param.Format, param.Decode, decoder); err != nil {
+			resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
+			return
+		}
+
+		if saveVal == val {
+			affect, err = client.ZAdd(ctx, key, redis.Z{
+				Score:  param.Score,
+				Member: saveVal,
+			}).Result()
+			displayValue, _, _ := convutil.ConvertTo(val, param.RetDecode, param.RetFormat, decoder)
+			if affect > 0 {
+				// add new item
+				added = append(added, types.ZSetEntryItem{
+					Score:        param.Score,
+					Value:        val,
+					DisplayValue: displayValue,
+				})
+			} else {
+				// update score only
+				updated = append(updated, types.ZSetEntryItem{
+					Score:        param.Score,
+					Value:        val,
+					DisplayValue: displayValue,
+				})
+			}
+		} else {
+			// remove old value and add new one
+			_, err = client.ZRem(ctx, key, val).Result()
+			if err != nil {
+				resp.Msg = err.Error()
+				return
+			}
+
+			affect, err = client.ZAdd(ctx, key, redis.Z{
+				Score:  param.Score,
+				Member: saveVal,
+			}).Result()
+			displayValue, _, _ := convutil.ConvertTo(saveVal, param.RetDecode, param.RetFormat, decoder)
+			if affect <= 0 {
+				// no new value added, just update exists item
+				removed = append(removed, types.ZSetEntryItem{
+					Value: val,
+				})
+				updated = append(updated, types.ZSetEntryItem{
+					Score:        param.Score,
+					Value:        saveVal,
+					DisplayValue: displayValue,
+				})
+			} else {
+				// add new field
+				replaced = append(replaced, types.ZSetReplaceItem{
+					Score:        param.Score,
+					Value:        val,
+					NewValue:     saveVal,
+					DisplayValue: displayValue,
+				})
+			}
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added    []types.ZSetEntryItem   `json:"added,omitempty"`
+		Updated  []types.ZSetEntryItem   `json:"updated,omitempty"`
+		Replaced []types.ZSetReplaceItem `json:"replaced,omitempty"`
+		Removed  []types.ZSetEntryItem   `json:"removed,omitempty"`
+	}{
+		Added:    added,
+		Updated:  updated,
+		Replaced: replaced,
+		Removed:  removed,
+	}
+	return
+}
+
+// AddZSetValue add item to sorted set
+func (b *browserService) AddZSetValue(server string, db int, k any, action int, valueScore map[string]float64) (resp types.JSResp) {
+	defer Audit().record(server, db, "add_zset", strutil.DecodeRedisKey(k), fmt.Sprintf("%d members", len(valueScore)), &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+
+	var added, updated []types.ZSetEntryItem
+	switch action {
+	case 1:
+		// ignore duplicated fields
+		for m, s := range valueScore {
+			if affect, _ := client.ZAddNX(ctx, key, redis.Z{Score: s, Member: m}).Result(); affect > 0 {
+				added = append(added, types.ZSetEntryItem{
+					Score:        s,
+					Value:        m,
+					DisplayValue: "", // TODO: convert to display value
+				})
+			}
+		}
+	default:
+		// overwrite duplicated fields
+		for m, s := range valueScore {
+			if affect, _ := client.ZAdd(ctx, key, redis.Z{Score: s, Member: m}).Result(); affect > 0 {
+				added = append(added, types.ZSetEntryItem{
+					Score:        s,
+					Value:        m,
+					DisplayValue: "", // TODO: convert to display value
+				})
+			} else {
+				updated = append(updated, types.ZSetEntryItem{
+					Score:        s,
+					Value:        m,
+					DisplayValue: "", // TODO: convert to display value
+				})
+			}
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added   []types.ZSetEntryItem `json:"added,omitempty"`
+		Updated []types.ZSetEntryItem `json:"updated,omitempty"`
+	}{
+		Added:   added,
+		Updated: updated,
+	}
+	return
+}
+
+// ScanZSetMembers scan one page of sorted set members with scores by ZSCAN, start with cursor 0
+func (b *browserService) ScanZSetMembers(server string, db int, k any, pattern string, cursor uint64, count int64) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	if len(pattern) <= 0 {
+		pattern = "*"
+	}
+	if count <= 0 {
+		count = int64(Preferences().GetScanSize())
+	}
+
+	loadedVal, nextCursor, err := client.ZScan(ctx, key, cursor, pattern, count).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	members := make([]types.ZSetEntryItem, 0, len(loadedVal)/2)
+	for i := 0; i+1 < len(loadedVal); i += 2 {
+		score, _ := strconv.ParseFloat(loadedVal[i+1], 64)
+		members = append(members, types.ZSetEntryItem{
+			Score:    score,
+			ScoreStr: loadedVal[i+1],
+			Value:    strutil.EncodeRedisKey(loadedVal[i]),
+		})
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Members []types.ZSetEntryItem `json:"members"`
+		Cursor  uint64                `json:"cursor"`
+		End     bool                  `json:"end"`
+	}{
+		Members: members,
+		Cursor:  nextCursor,
+		End:     nextCursor == 0,
+	}
+	return
+}
+
+// normalize score bound of sorted set range, support "-inf", "+inf" and exclusive prefix "("
+func (b *browserService) parseScoreBound(bound string) (string, error) {
+	bound = strings.TrimSpace(bound)
+	num := strings.TrimPrefix(bound, "(")
+	switch strings.ToLower(num) {
+	case "-inf":
+		return "-inf", nil
+	case "inf", "+inf":
+		return "+inf", nil
+	}
+	if _, err := strconv.ParseFloat(num, 64); err != nil {
+		return "", fmt.Errorf("invalid score bound: %s", bound)
+	}
+	return bound, nil
+}
+
+// GetZSetRangeByScore get members of sorted set with scores in range [min, max]
+// @param minScore min score, "-inf" for no limit, exclusive if prefixed by "("
+// @param maxScore max score, "+inf" for no limit, exclusive if prefixed by "("
+// @param desc order by score descending
+func (b *browserService) GetZSetRangeByScore(server string, db int, k any, minScore, maxScore string, offset, count int64, desc bool) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	if minScore, err = b.parseScoreBound(minScore); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if maxScore, err = b.parseScoreBound(maxScore); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if count <= 0 {
+		// no limit
+		offset, count = 0, -1
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	rangeBy := &redis.ZRangeBy{
+		Min:    minScore,
+		Max:    maxScore,
+		Offset: offset,
+		Count:  count,
+	}
+	var loaded []redis.Z
+	if desc {
+		loaded, err = client.ZRevRangeByScoreWithScores(ctx, key, rangeBy).Result()
+	} else {
+		loaded, err = client.ZRangeByScoreWithScores(ctx, key, rangeBy).Result()
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	members := sliceutil.Map(loaded, func(i int) types.ZSetEntryItem {
+		return types.ZSetEntryItem{
+			Score:    loaded[i].Score,
+			ScoreStr: strconv.FormatFloat(loaded[i].Score, 'f', -1, 64),
+			Value:    strutil.EncodeRedisKey(loaded[i].Member.(string)),
+		}
+	})
+	resp.Success = true
+	resp.Data = struct {
+		Members []types.ZSetEntryItem `json:"members"`
+	}{
+		Members: members,
+	}
+	return
+}
+
+// ZSetUpdateScore update score of sorted set member, the member will be added if not exists
+func (b *browserService) ZSetUpdateScore(server string, db int, k any, member any, score float64) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	added, err := client.ZAdd(ctx, key, redis.Z{
+		Score:  score,
+		Member: strutil.DecodeRedisKey(member),
+	}).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Added bool `json:"added"`
+	}{
+		Added: added > 0,
+	}
+	return
+}
+
+// ZSetRemoveMember remove one member from sorted set
+func (b *browserService) ZSetRemoveMember(server string, db int, k any, member any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	removed, err := client.ZRem(ctx, key, strutil.DecodeRedisKey(member)).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if removed <= 0 {
+		resp.Msg = "member not exists"
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// parse stream entry id "<milliseconds>-<sequence>"
+func (b *browserService) parseStreamID(id string) (ms, seq int64) {
+	msStr, seqStr, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseInt(msStr, 10, 64)
+	seq, _ = strconv.ParseInt(seqStr, 10, 64)
+	return
+}
+
+// GetStreamEntries get one page of stream entries by XRANGE, or XREVRANGE if reverse
+// @param start start id, "-" for the first entry, exclusive if prefixed by "("
+// @param end end id, "+" for the last entry, exclusive if prefixed by "("
+func (b *browserService) GetStreamEntries(server string, db int, k any, start, end string, count int64, reverse bool) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	if len(start) <= 0 {
+		start = "-"
+	}
+	if len(end) <= 0 {
+		end = "+"
+	}
+	if count <= 0 {
+		count = int64(Preferences().GetScanSize())
+	}
+
+	var msgs []redis.XMessage
+	if reverse {
+		// XREVRANGE accept arguments in order of end and start
+		msgs, err = client.XRevRangeN(ctx, key, end, start, count).Result()
+	} else {
+		msgs, err = client.XRangeN(ctx, key, start, end, count).Result()
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	type streamEntry struct {
+		ID        string         `json:"id"`
+		Timestamp int64          `json:"timestamp"` // milliseconds part of id
+		Seq       int64          `json:"seq"`       // sequence part of id
+		Fields    map[string]any `json:"fields"`
+	}
+	entries := sliceutil.Map(msgs, func(i int) streamEntry {
+		ms, seq := b.parseStreamID(msgs[i].ID)
+		return streamEntry{
+			ID:        msgs[i].ID,
+			Timestamp: ms,
+			Seq:       seq,
+			Fields:    msgs[i].Values,
+		}
+	})
+
+	resp.Success = true
+	resp.Data = struct {
+		Entries any  `json:"entries"`
+		End     bool `json:"end"`
+	}{
+		Entries: entries,
+		End:     int64(len(entries)) < count,
+	}
+	return
+}
+
+// GetStreamGroups get consumer groups of stream with their consumers by XINFO GROUPS/CONSUMERS
+func (b *browserService) GetStreamGroups(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	groups, err := client.XInfoGroups(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	type streamConsumer struct {
+		Name     string `json:"name"`
+		Pending  int64  `json:"pending"`
+		Idle     int64  `json:"idle"`     // milliseconds since last attempted interaction
+		Inactive int64  `json:"inactive"` // milliseconds since last successful interaction, -1 if never or unsupported
+	}
+	type streamGroup struct {
+		Name            string           `json:"name"`
+		Consumers       []streamConsumer `json:"consumers"`
+		Pending         int64            `json:"pending"`
+		LastDeliveredID string           `json:"lastDeliveredId"`
+		EntriesRead     int64            `json:"entriesRead"`
+		Lag             int64            `json:"lag"` // -1 if unknown or unsupported
+	}
+	result := make([]streamGroup, 0, len(groups))
+	for _, g := range groups {
+		group := streamGroup{
+			Name:            g.Name,
+			Pending:         g.Pending,
+			LastDeliveredID: g.LastDeliveredID,
+			EntriesRead:     g.EntriesRead,
+			Lag:             g.Lag,
+			Consumers:       []streamConsumer{},
+		}
+		if consumers, subErr := client.XInfoConsumers(ctx, key, g.Name).Result(); subErr == nil {
+			for _, c := range consumers {
+				inactive := int64(-1)
+				if c.Inactive > 0 {
+					inactive = c.Inactive.Milliseconds()
+				}
+				group.Consumers = append(group.Consumers, streamConsumer{
+					Name:     c.Name,
+					Pending:  c.Pending,
+					Idle:     c.Idle.Milliseconds(),
+					Inactive: inactive,
+				})
+			}
+		}
+		result = append(result, group)
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Groups []streamGroup `json:"groups"`
+	}{
+		Groups: result,
+	}
+	return
+}
+
+// check if module is loaded by MODULE LIST
+func (b *browserService) hasModule(ctx context.Context, client redis.UniversalClient, name string) (bool, error) {
+	modules, err := client.Do(ctx, "MODULE", "LIST").Slice()
+	if err != nil {
+		return false, err
+	}
+	for _, module := range modules {
+		// RESP3 reply each module as map, RESP2 as array of alternate key and value
+		var moduleName any
+		switch m := module.(type) {
+		case map[any]any:
+			moduleName = m["name"]
+		case map[string]any:
+			moduleName = m["name"]
+		case []any:
+			for i := 0; i+1 < len(m); i += 2 {
+				if k, _ := m[i].(string); k == "name" {
+					moduleName = m[i+1]
+				}
+			}
+		}
+		if n, ok := moduleName.(string); ok && strings.EqualFold(n, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// check RedisJSON module is available
+func (b *browserService) checkJSONModule(ctx context.Context, client redis.UniversalClient) error {
+	if ok, err := b.hasModule(ctx, client, "ReJSON"); err != nil {
+		return fmt.Errorf("check RedisJSON module fail: %s", err.Error())
+	} else if !ok {
+		return errors.New("RedisJSON module is not loaded")
+	}
+	return nil
+}
+
+// JsonGet get json value at path by JSON.GET, returns pretty-printed json
+// path start with "$" is JSONPath(RedisJSON v2) which always returns an array of matched values,
+// otherwise is legacy path(RedisJSON v1) which returns the single value
+func (b *browserService) JsonGet(server string, db int, k any, path string) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if err = b.checkJSONModule(ctx, client); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if len(path) <= 0 {
+		path = "$"
+	}
+
+	key := strutil.DecodeRedisKey(k)
+	content, err := client.JSONGet(ctx, key, path).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if len(content) <= 0 {
+		resp.Msg = "key not exists"
+		return
+	}
+
+	// indent in place to keep the original order of object fields
+	var pretty bytes.Buffer
+	if err = json.Indent(&pretty, []byte(content), "", "  "); err != nil {
+		resp.Msg = "invalid json reply: " + err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Value    string `json:"value"`
+		Multiple bool   `json:"multiple"` // value is an array of all values matched by JSONPath
+	}{
+		Value:    pretty.String(),
+		Multiple: strings.HasPrefix(path, "$"),
+	}
+	return
+}
+
+// JsonSet set json value at path by JSON.SET, the key will be created if path is root
+func (b *browserService) JsonSet(server string, db int, k any, path string, value string) (resp types.JSResp) {
+	defer Audit().record(server, db, "set_json", strutil.DecodeRedisKey(k), "path "+path, &resp)
+	if !json.Valid([]byte(value)) {
+		resp.Msg = "invalid json value"
+		return
+	}
+
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if err = b.checkJSONModule(ctx, client); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if len(path) <= 0 {
+		path = "$"
+	}
+
+	key := strutil.DecodeRedisKey(k)
+	if err = client.JSONSet(ctx, key, path, value).Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			resp.Msg = "path not exists"
+		} else {
+			resp.Msg = err.Error()
+		}
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// check RedisBloom module is available
+func (b *browserService) checkBloomModule(ctx context.Context, client redis.UniversalClient) error {
+	if ok, err := b.hasModule(ctx, client, "bf"); err != nil {
+		return fmt.Errorf("check RedisBloom module fail: %s", err.Error())
+	} else if !ok {
+		return errors.New("RedisBloom module is not loaded")
+	}
+	return nil
+}
+
+// get kind of probabilistic key by TYPE, returns empty string if not supported
+func (b *browserService) getBloomKind(ctx context.Context, client redis.UniversalClient, key string) (string, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+	switch keyType {
+	case "none":
+		return "", errors.New("key not exists")
+	case "MBbloom--":
+		return "bloom", nil
+	case "MBbloomCF":
+		return "cuckoo", nil
+	case "CMSk-TYPE":
+		return "cms", nil
+	}
+	return "", fmt.Errorf("not a RedisBloom key: %s", keyType)
+}
+
+type bloomInfo struct {
+	Kind          string `json:"kind"` // bloom/cuckoo/cms
+	Capacity      int64  `json:"capacity,omitempty"`
+	Size          int64  `json:"size,omitempty"` // memory usage in bytes
+	Filters       int64  `json:"filters,omitempty"`
+	Items         int64  `json:"items"` // number of items inserted, or total count of Count-Min sketch
+	Deleted       int64  `json:"deleted,omitempty"`
+	Buckets       int64  `json:"buckets,omitempty"`
+	BucketSize    int64  `json:"bucketSize,omitempty"`
+	ExpansionRate int64  `json:"expansionRate,omitempty"`
+	MaxIteration  int64  `json:"maxIteration,omitempty"`
+	Width         int64  `json:"width,omitempty"`
+	Depth         int64  `json:"depth,omitempty"`
+}
+
+// GetBloomInfo get stats of Bloom filter, Cuckoo filter or Count-Min sketch by BF.INFO/CF.INFO/CMS.INFO
+// error rate is not reported by BF.INFO, so it's absent from the result
+func (b *browserService) GetBloomInfo(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if err = b.checkBloomModule(ctx, client); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	key := strutil.DecodeRedisKey(k)
+	kind, err := b.getBloomKind(ctx, client, key)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	info := bloomInfo{Kind: kind}
+	switch kind {
+	case "bloom":
+		var res redis.BFInfo
+		if res, err = client.BFInfo(ctx, key).Result(); err == nil {
+			info.Capacity = res.Capacity
+			info.Size = res.Size
+			info.Filters = res.Filters
+			info.Items = res.ItemsInserted
+			info.ExpansionRate = res.ExpansionRate
+		}
+	case "cuckoo":
+		var res redis.CFInfo
+		if res, err = client.CFInfo(ctx, key).Result(); err == nil {
+			info.Capacity = res.NumBuckets * res.BucketSize
+			info.Size = res.Size
+			info.Filters = res.NumFilters
+			info.Items = res.NumItemsInserted
+			info.Deleted = res.NumItemsDeleted
+			info.Buckets = res.NumBuckets
+			info.BucketSize = res.BucketSize
+			info.ExpansionRate = res.ExpansionRate
+			info.MaxIteration = res.MaxIteration
+		}
+	case "cms":
+		var res redis.CMSInfo
+		if res, err = client.CMSInfo(ctx, key).Result(); err == nil {
+			info.Width = res.Width
+			info.Depth = res.Depth
+			info.Items = res.Count
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = info
+	return
+}
+
+// BloomTest test items exist in Bloom or Cuckoo filter by BF.MEXISTS/CF.MEXISTS,
+// or add items by BF.MADD/CF.INSERT if add is set
+// @param add add items to filter instead of testing existence
+func (b *browserService) BloomTest(server string, db int, k any, items []string, add bool) (resp types.JSResp) {
+	if add {
+		defer Audit().record(server, db, "add_bloom", strutil.DecodeRedisKey(k), fmt.Sprintf("%d items", len(items)), &resp)
+	}
+	if len(items) <= 0 {
+		resp.Msg = "no item to test"
+		return
+	}
+
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if err = b.checkBloomModule(ctx, client); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	key := strutil.DecodeRedisKey(k)
+	kind, err := b.getBloomKind(ctx, client, key)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	args := sliceutil.Map(items, func(i int) any {
+		return items[i]
+	})
+	var results []bool
+	switch kind {
+	case "bloom":
+		if add {
+			results, err = client.BFMAdd(ctx, key, args...).Result()
+		} else {
+			results, err = client.BFMExists(ctx, key, args...).Result()
+		}
+	case "cuckoo":
+		if add {
+			results, err = client.CFInsert(ctx, key, nil, args...).Result()
+		} else {
+			results, err = client.CFMExists(ctx, key, args...).Result()
+		}
+	default:
+		err = errors.New("only Bloom and Cuckoo filter can be tested")
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	type testResult struct {
+		Item   string `json:"item"`
+		Result bool   `json:"result"` // item may exist if testing, or item is newly added if adding
+	}
+	testResults := make([]testResult, 0, len(items))
+	for i, it := range items {
+		if i < len(results) {
+			testResults = append(testResults, testResult{Item: it, Result: results[i]})
+		}
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Kind    string       `json:"kind"`
+		Added   bool         `json:"added"`
+		Results []testResult `json:"results"`
+	}{
+		Kind:    kind,
+		Added:   add,
+		Results: testResults,
+	}
+	return
+}
+
+// check if key is a HyperLogLog, which is stored as string and accepted by PFCOUNT
+func (b *browserService) isHLL(ctx context.Context, client redis.UniversalClient, key string) (bool, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if keyType == "none" {
+		return false, errors.New("key not exists")
+	}
+	if keyType != "string" {
+		return false, nil
+	}
+	if err = client.PFCount(ctx, key).Err(); err != nil {
+		if strings.HasPrefix(err.Error(), "WRONGTYPE") || strings.HasPrefix(err.Error(), "INVALIDOBJ") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
 
-		data.Value, data.Reset, data.End, err = loadStreamHandle()
-		data.Match, data.Decode, data.Format = param.MatchPattern, param.Decode, param.Format
-		if err != nil {
-			resp.Msg = err.Error()
+// GetHLLCount get approximate cardinality of HyperLogLog key by PFCOUNT
+func (b *browserService) GetHLLCount(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	if ok, err := b.isHLL(ctx, client, key); err != nil {
+		resp.Msg = err.Error()
+		return
+	} else if !ok {
+		resp.Msg = "not a HyperLogLog key"
+		return
+	}
+
+	count, err := client.PFCount(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Count       int64 `json:"count"`
+		Approximate bool  `json:"approximate"`
+	}{
+		Count:       count,
+		Approximate: true,
+	}
+	return
+}
+
+// MergeHLL merge multiple HyperLogLog keys into destination key by PFMERGE
+func (b *browserService) MergeHLL(server string, db int, destKey any, srcKeys []any) (resp types.JSResp) {
+	defer Audit().record(server, db, "merge_hll", strutil.DecodeRedisKey(destKey), fmt.Sprintf("from %d keys", len(srcKeys)), &resp)
+	if len(srcKeys) <= 0 {
+		resp.Msg = "no source key"
+		return
+	}
+
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	dest := strutil.DecodeRedisKey(destKey)
+	keys := make([]string, 0, len(srcKeys))
+	for _, k := range srcKeys {
+		key := strutil.DecodeRedisKey(k)
+		if ok, err := b.isHLL(ctx, client, key); err != nil {
+			resp.Msg = fmt.Sprintf("%s: %s", key, err.Error())
+			return
+		} else if !ok {
+			resp.Msg = fmt.Sprintf("%s: not a HyperLogLog key", key)
 			return
 		}
+		keys = append(keys, key)
+	}
 
-	case "rejson-rl":
-		var jsonStr string
-		data.KeyType = "JSON"
-		jsonStr, err = client.JSONGet(ctx, key).Result()
-		data.Value, data.Decode, data.Format = convutil.ConvertTo(jsonStr, types.DECODE_NONE, types.FORMAT_JSON, nil)
+	if err = client.PFMerge(ctx, dest, keys...).Err(); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	count, _ := client.PFCount(ctx, dest).Result()
+
+	resp.Success = true
+	resp.Data = struct {
+		Count       int64 `json:"count"`
+		Approximate bool  `json:"approximate"`
+	}{
+		Count:       count,
+		Approximate: true,
+	}
+	return
+}
+
+// GetBitmap get bit layout of string key in byte range [start, end], end is inclusive
+// only requested range is loaded, at most MAX_BITMAP_PAGE_SIZE bytes per page
+func (b *browserService) GetBitmap(server string, db int, k any, start, end int64) (resp types.JSResp) {
+	if start < 0 {
+		start = 0
+	}
+	if end < start {
+		resp.Msg = "invalid byte range"
+		return
 	}
+	if end-start+1 > consts.MAX_BITMAP_PAGE_SIZE {
+		end = start + consts.MAX_BITMAP_PAGE_SIZE - 1
+	}
+
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	pipe := client.Pipeline()
+	lenCmd := pipe.StrLen(ctx, key)
+	rangeCmd := pipe.GetRange(ctx, key, start, end)
+	totalCmd := pipe.BitCount(ctx, key, nil)
+	pageCmd := pipe.BitCount(ctx, key, &redis.BitCount{Start: start, End: end})
+	if _, err = pipe.Exec(ctx); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	// bits in big-endian order of each byte, same as offset used by SETBIT/GETBIT
+	bin := rangeCmd.Val()
+	var bits strings.Builder
+	bits.Grow(len(bin) * 8)
+	for i := 0; i < len(bin); i++ {
+		bits.WriteString(fmt.Sprintf("%08b", bin[i]))
+	}
+
 	resp.Success = true
-	resp.Data = data
+	resp.Data = struct {
+		Bits      string `json:"bits"`
+		Start     int64  `json:"start"`
+		End       int64  `json:"end"`
+		Length    int64  `json:"length"` // total length of string in bytes
+		Count     int64  `json:"count"`  // total count of set bits
+		PageCount int64  `json:"pageCount"`
+	}{
+		Bits:      bits.String(),
+		Start:     start,
+		End:       start + int64(len(bin)) - 1,
+		Length:    lenCmd.Val(),
+		Count:     totalCmd.Val(),
+		PageCount: pageCmd.Val(),
+	}
 	return
 }
 
-// ConvertValue convert value with decode method and format
-// blank decode indicate auto decode
-// blank format indicate auto format
-func (b *browserService) ConvertValue(value any, decode, format string) (resp types.JSResp) {
-	str := strutil.DecodeRedisKey(value)
-	value, decode, format = convutil.ConvertTo(str, decode, format, Preferences().GetDecoder())
+// SetBit set or clear bit at offset, returns original bit value
+func (b *browserService) SetBit(server string, db int, k any, offset int64, value int) (resp types.JSResp) {
+	defer Audit().record(server, db, "set_bit", strutil.DecodeRedisKey(k), fmt.Sprintf("offset %d to %d", offset, value), &resp)
+	if value != 0 && value != 1 {
+		resp.Msg = "bit value must be 0 or 1"
+		return
+	}
+
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	orig, err := client.SetBit(ctx, key, offset, value).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
 	resp.Success = true
-	resp.Data = map[string]any{
-		"value":  value,
-		"decode": decode,
-		"format": format,
+	resp.Data = struct {
+		Original int64 `json:"original"`
+	}{
+		Original: orig,
 	}
 	return
 }
 
-// SetKeyValue set value by key
-// @param ttl <= 0 means keep current ttl
-func (b *browserService) SetKeyValue(param types.SetKeyParam) (resp types.JSResp) {
-	item, err := b.getRedisClient(param.Server, param.DB)
+// BitField exec single BITFIELD operation
+// @param op get/set/incrby
+// @param encoding integer encoding like "u8", "i16"
+// @param offset bit offset, "#N" means multiply by width of encoding
+// @param value value to set or increase, ignored for get
+func (b *browserService) BitField(server string, db int, k any, op, encoding, offset string, value int64) (resp types.JSResp) {
+	args := []any{strings.ToUpper(op), encoding, offset}
+	switch strings.ToLower(op) {
+	case "get":
+	case "set", "incrby":
+		args = append(args, value)
+	default:
+		resp.Msg = "unsupported bitfield operation: " + op
+		return
+	}
+
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
 	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(param.Key)
-	var expiration time.Duration
-	if param.TTL < 0 {
-		if expiration, err = client.PTTL(ctx, key).Result(); err != nil {
-			expiration = redis.KeepTTL
-		}
-	} else {
-		expiration = time.Duration(param.TTL) * time.Second
+	key := strutil.DecodeRedisKey(k)
+	results, err := client.BitField(ctx, key, args...).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
 	}
-	// use default decode type and format
-	if len(param.Decode) <= 0 {
-		param.Decode = types.DECODE_NONE
+	if len(results) <= 0 {
+		resp.Msg = "empty bitfield reply"
+		return
 	}
-	if len(param.Format) <= 0 {
-		param.Format = types.FORMAT_RAW
+
+	resp.Success = true
+	resp.Data = struct {
+		Value int64 `json:"value"`
+	}{
+		Value: results[0],
 	}
-	var savedValue any
-	switch strings.ToLower(param.KeyType) {
-	case "string":
-		if str, ok := param.Value.(string); !ok {
-			resp.Msg = "invalid string value"
-			return
-		} else {
-			if savedValue, err = convutil.SaveAs(str, param.Format, param.Decode, Preferences().GetDecoder()); err != nil {
-				resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
-				return
-			}
-			_, err = client.Set(ctx, key, savedValue, 0).Result()
-			// set expiration lonely, not "keepttl"
-			if err == nil && expiration > 0 {
-				client.Expire(ctx, key, expiration)
-			}
-		}
-	case "list":
-		if strs, ok := param.Value.([]any); !ok {
-			resp.Msg = "invalid list value"
-			return
-		} else {
-			err = client.LPush(ctx, key, strs...).Err()
-			if err == nil && expiration > 0 {
-				client.Expire(ctx, key, expiration)
-			}
-		}
-	case "hash":
-		if strs, ok := param.Value.([]any); !ok {
-			resp.Msg = "invalid hash value"
+	return
+}
+
+// GetGeoMembers get positions of all members in geo key(sorted set)
+// members without valid position are returned with valid=false
+func (b *browserService) GetGeoMembers(server string, db int, k any) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	type geoMember struct {
+		Member    string  `json:"member"`
+		Longitude float64 `json:"longitude"`
+		Latitude  float64 `json:"latitude"`
+		Valid     bool    `json:"valid"`
+	}
+	var members []geoMember
+	const batchSize = 1000
+	for start := int64(0); ; start += batchSize {
+		var names []string
+		if names, err = client.ZRange(ctx, key, start, start+batchSize-1).Result(); err != nil {
+			resp.Msg = err.Error()
 			return
-		} else {
-			total := len(strs)
-			if total > 1 {
-				_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
-					for i := 0; i < total; i += 2 {
-						pipe.HSet(ctx, key, strs[i], strs[i+1])
-					}
-					if expiration > 0 {
-						pipe.Expire(ctx, key, expiration)
-					}
-					return nil
-				})
-			}
 		}
-	case "set":
-		if strs, ok := param.Value.([]any); !ok || len(strs) <= 0 {
-			resp.Msg = "invalid set value"
-			return
-		} else {
-			if len(strs) > 0 {
-				err = client.SAdd(ctx, key, strs...).Err()
-				if err == nil && expiration > 0 {
-					client.Expire(ctx, key, expiration)
-				}
-			}
+		if len(names) <= 0 {
+			break
 		}
-	case "zset":
-		if strs, ok := param.Value.([]any); !ok || len(strs) <= 0 {
-			resp.Msg = "invalid zset value"
+		var positions []*redis.GeoPos
+		if positions, err = client.GeoPos(ctx, key, names...).Result(); err != nil {
+			resp.Msg = err.Error()
 			return
-		} else {
-			if len(strs) > 1 {
-				var members []redis.Z
-				for i := 0; i < len(strs); i += 2 {
-					score, _ := strconv.ParseFloat(strs[i+1].(string), 64)
-					members = append(members, redis.Z{
-						Score:  score,
-						Member: strs[i],
-					})
-				}
-				err = client.ZAdd(ctx, key, members...).Err()
-				if err == nil && expiration > 0 {
-					client.Expire(ctx, key, expiration)
-				}
-			}
 		}
-	case "stream":
-		if strs, ok := param.Value.([]any); !ok {
-			resp.Msg = "invalid stream value"
-			return
-		} else {
-			if len(strs) > 2 {
-				err = client.XAdd(ctx, &redis.XAddArgs{
-					Stream: key,
-					ID:     strs[0].(string),
-					Values: strs[1:],
-				}).Err()
-				if err == nil && expiration > 0 {
-					client.Expire(ctx, key, expiration)
-				}
+		for i, name := range names {
+			m := geoMember{Member: name}
+			// score of member not added by GEOADD may be out of valid coordinate range
+			if i < len(positions) && positions[i] != nil {
+				m.Longitude, m.Latitude, m.Valid = positions[i].Longitude, positions[i].Latitude, true
 			}
+			members = append(members, m)
 		}
-	case "json":
-		err = client.JSONSet(ctx, key, ".", param.Value).Err()
-		if err == nil && expiration > 0 {
-			client.Expire(ctx, key, expiration)
-		}
-		var ok bool
-		if savedValue, ok = param.Value.(string); !ok {
-			savedValue = ""
+		if len(names) < batchSize {
+			break
 		}
 	}
 
+	resp.Success = true
+	resp.Data = struct {
+		Members []geoMember `json:"members"`
+	}{
+		Members: members,
+	}
+	return
+}
+
+// GeoAdd add or update member position in geo key
+func (b *browserService) GeoAdd(server string, db int, k any, member string, longitude, latitude float64) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	added, err := client.GeoAdd(ctx, key, &redis.GeoLocation{
+		Name:      member,
+		Longitude: longitude,
+		Latitude:  latitude,
+	}).Result()
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+
 	resp.Success = true
-	respData := map[string]any{}
-	if val, ok := savedValue.(string); ok {
-		respData["value"] = strutil.EncodeRedisKey(val)
+	resp.Data = struct {
+		Added bool `json:"added"`
+	}{
+		Added: added > 0,
 	}
-	resp.Data = respData
 	return
 }
 
-// GetHashValue get hash field
-func (b *browserService) GetHashValue(param types.GetHashParam) (resp types.JSResp) {
+// GeoSearch search members in geo key within radius or box by GEOSEARCH
+func (b *browserService) GeoSearch(param types.GeoSearchParam) (resp types.JSResp) {
+	query := redis.GeoSearchQuery{
+		Member:     param.Member,
+		Longitude:  param.Longitude,
+		Latitude:   param.Latitude,
+		Count:      param.Count,
+		RadiusUnit: param.Unit,
+		BoxUnit:    param.Unit,
+	}
+	if len(query.RadiusUnit) <= 0 {
+		query.RadiusUnit, query.BoxUnit = "m", "m"
+	}
+	if param.Radius > 0 {
+		query.Radius = param.Radius
+	} else if param.Width > 0 && param.Height > 0 {
+		query.BoxWidth, query.BoxHeight = param.Width, param.Height
+	} else {
+		resp.Msg = "radius or box size is required"
+		return
+	}
+	if param.Desc {
+		query.Sort = "DESC"
+	} else {
+		query.Sort = "ASC"
+	}
+
 	item, err := b.getRedisClient(param.Server, param.DB)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -1370,109 +4407,101 @@ func (b *browserService) GetHashValue(param types.GetHashParam) (resp types.JSRe
 
 	client, ctx := item.client, item.ctx
 	key := strutil.DecodeRedisKey(param.Key)
-	val, err := client.HGet(ctx, key, param.Field).Result()
-	if errors.Is(err, redis.Nil) {
-		resp.Msg = "field in key not found"
-		return
-	}
+	locations, err := client.GeoSearchLocation(ctx, key, &redis.GeoSearchLocationQuery{
+		GeoSearchQuery: query,
+		WithCoord:      true,
+		WithDist:       true,
+	}).Result()
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
-	var displayVal string
-	if (len(param.Decode) > 0 && param.Decode != types.DECODE_NONE) ||
-		(len(param.Format) > 0 && param.Format != types.FORMAT_RAW) {
-		decoder := Preferences().GetDecoder()
-		displayVal, _, _ = convutil.ConvertTo(val, param.Decode, param.Format, decoder)
-		if displayVal == val {
-			displayVal = ""
-		}
+	type geoLocation struct {
+		Member    string  `json:"member"`
+		Longitude float64 `json:"longitude"`
+		Latitude  float64 `json:"latitude"`
+		Distance  float64 `json:"distance"`
 	}
-
-	resp.Data = types.HashEntryItem{
-		Key:          param.Field,
-		Value:        val,
-		DisplayValue: displayVal,
+	results := make([]geoLocation, 0, len(locations))
+	for _, loc := range locations {
+		results = append(results, geoLocation{
+			Member:    loc.Name,
+			Longitude: loc.Longitude,
+			Latitude:  loc.Latitude,
+			Distance:  loc.Dist,
+		})
 	}
+
 	resp.Success = true
+	resp.Data = struct {
+		Results []geoLocation `json:"results"`
+		Unit    string        `json:"unit"`
+	}{
+		Results: results,
+		Unit:    query.RadiusUnit,
+	}
 	return
 }
 
-// SetHashValue update hash field
-func (b *browserService) SetHashValue(param types.SetHashParam) (resp types.JSResp) {
-	item, err := b.getRedisClient(param.Server, param.DB)
+// AddStreamValue add stream field
+func (b *browserService) AddStreamValue(server string, db int, k any, ID string, fieldItems []any) (resp types.JSResp) {
+	defer Audit().record(server, db, "add_stream", strutil.DecodeRedisKey(k), "id "+ID, &resp)
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
 	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(param.Key)
-	str := strutil.DecodeRedisKey(param.Value)
-	var saveStr, displayStr string
-	decoder := Preferences().GetDecoder()
-	if saveStr, err = convutil.SaveAs(str, param.Format, param.Decode, decoder); err != nil {
-		resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
+	key := strutil.DecodeRedisKey(k)
+	var updateID string
+	updateID, err = client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		ID:     ID,
+		Values: fieldItems,
+	}).Result()
+	if err != nil {
+		resp.Msg = err.Error()
 		return
 	}
-	if len(param.RetDecode) > 0 && len(param.RetFormat) > 0 {
-		displayStr, _, _ = convutil.ConvertTo(saveStr, param.RetDecode, param.RetFormat, decoder)
+
+	updateValues := make(map[string]any, len(fieldItems)/2)
+	for i := 0; i < len(fieldItems)/2; i += 2 {
+		updateValues[fieldItems[i].(string)] = fieldItems[i+1]
 	}
-	var updated, added, removed []types.HashEntryItem
-	var replaced []types.HashReplaceItem
-	var affect int64
-	if len(param.NewField) <= 0 {
-		// new field is empty, delete old field
-		_, err = client.HDel(ctx, key, param.Field).Result()
-		removed = append(removed, types.HashEntryItem{
-			Key: param.Field,
-		})
-	} else if len(param.Field) <= 0 || param.Field == param.NewField {
-		affect, err = client.HSet(ctx, key, param.NewField, saveStr).Result()
-		if affect <= 0 {
-			// update field value
-			updated = append(updated, types.HashEntryItem{
-				Key:          param.NewField,
-				Value:        saveStr,
-				DisplayValue: displayStr,
-			})
-		} else {
-			// add new field
-			added = append(added, types.HashEntryItem{
-				Key:          param.NewField,
-				Value:        saveStr,
-				DisplayValue: displayStr,
-			})
-		}
-	} else {
-		// remove old field and add new field
-		if _, err = client.HDel(ctx, key, param.Field).Result(); err != nil {
-			resp.Msg = err.Error()
-			return
-		}
+	vb, _ := json.Marshal(updateValues)
+	displayValue, _, _ := convutil.ConvertTo(string(vb), types.DECODE_NONE, types.FORMAT_JSON, Preferences().GetDecoder())
 
-		affect, err = client.HSet(ctx, key, param.NewField, saveStr).Result()
-		if affect <= 0 {
-			// no new filed added, just update exists item
-			removed = append(removed, types.HashEntryItem{
-				Key: param.Field,
-			})
-			updated = append(updated, types.HashEntryItem{
-				Key:          param.NewField,
-				Value:        saveStr,
-				DisplayValue: displayStr,
-			})
-		} else {
-			// add new field
-			replaced = append(replaced, types.HashReplaceItem{
-				Key:          param.Field,
-				NewKey:       param.NewField,
-				Value:        saveStr,
-				DisplayValue: displayStr,
-			})
-		}
+	resp.Success = true
+	resp.Data = struct {
+		Added []types.StreamEntryItem `json:"added,omitempty"`
+	}{
+		Added: []types.StreamEntryItem{
+			{
+				ID:           updateID,
+				Value:        updateValues,
+				DisplayValue: displayValue, // TODO: convert to display value
+			},
+		},
+	}
+	return
+}
+
+// RemoveStreamValues remove stream values by id
+func (b *browserService) RemoveStreamValues(server string, db int, k any, IDs []string) (resp types.JSResp) {
+	defer Audit().record(server, db, "delete_stream", strutil.DecodeRedisKey(k), "ids "+strings.Join(IDs, ","), &resp)
+	item, err := b.getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
 	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+
+	var affected int64
+	affected, err = client.XDel(ctx, key, IDs...).Result()
 	if err != nil {
 		resp.Msg = err.Error()
 		return
@@ -1480,21 +4509,16 @@ func (b *browserService) SetHashValue(param types.SetHashParam) (resp types.JSRe
 
 	resp.Success = true
 	resp.Data = struct {
-		Added    []types.HashEntryItem   `json:"added,omitempty"`
-		Removed  []types.HashEntryItem   `json:"removed,omitempty"`
-		Updated  []types.HashEntryItem   `json:"updated,omitempty"`
-		Replaced []types.HashReplaceItem `json:"replaced,omitempty"`
+		Affected int64 `json:"affected"`
 	}{
-		Added:    added,
-		Removed:  removed,
-		Updated:  updated,
-		Replaced: replaced,
+		Affected: affected,
 	}
 	return
 }
 
-// AddHashField add or update hash field
-func (b *browserService) AddHashField(server string, db int, k any, action int, fieldItems []any) (resp types.JSResp) {
+// SetKeyTTL set ttl of key
+func (b *browserService) SetKeyTTL(server string, db int, k any, ttl int64) (resp types.JSResp) {
+	defer Audit().record(server, db, "ttl", strutil.DecodeRedisKey(k), fmt.Sprintf("%d seconds", ttl), &resp)
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -1503,61 +4527,152 @@ func (b *browserService) AddHashField(server string, db int, k any, action int,
 
 	client, ctx := item.client, item.ctx
 	key := strutil.DecodeRedisKey(k)
-	var updated []types.HashEntryItem
-	var added []types.HashEntryItem
-	switch action {
-	case 1:
-		// ignore duplicated fields
-		for i := 0; i < len(fieldItems); i += 2 {
-			field, value := strutil.DecodeRedisKey(fieldItems[i]), strutil.DecodeRedisKey(fieldItems[i+1])
-			if succ, _ := client.HSetNX(ctx, key, field, value).Result(); succ {
-				added = append(added, types.HashEntryItem{
-					Key:          field,
-					Value:        value,
-					DisplayValue: "", // TODO: convert to display value
-				})
-			}
+	if ttl < 0 {
+		// PERSIST also returns 0 if key has no ttl, check existence first
+		var exists int64
+		if exists, err = client.Exists(ctx, key).Result(); err != nil {
+			resp.Msg = err.Error()
+			return
+		} else if exists <= 0 {
+			resp.Msg = "key not exists"
+			return
 		}
-	default:
-		// overwrite duplicated fields
-		total := len(fieldItems)
-		if total > 1 {
-			for i := 0; i < total; i += 2 {
-				field, value := strutil.DecodeRedisKey(fieldItems[i]), strutil.DecodeRedisKey(fieldItems[i+1])
-				if affect, _ := client.HSet(ctx, key, field, value).Result(); affect > 0 {
-					added = append(added, types.HashEntryItem{
-						Key:          field,
-						Value:        value,
-						DisplayValue: "", // TODO: convert to display value
-					})
-				} else {
-					updated = append(updated, types.HashEntryItem{
-						Key:          field,
-						Value:        value,
-						DisplayValue: "", // TODO: convert to display value
-					})
-				}
-			}
+		if err = client.Persist(ctx, key).Err(); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+	} else {
+		var ok bool
+		expiration := time.Duration(ttl) * time.Second
+		if ok, err = client.Expire(ctx, key, expiration).Result(); err != nil {
+			resp.Msg = err.Error()
+			return
+		} else if !ok {
+			resp.Msg = "key not exists"
+			return
 		}
 	}
+
+	resp.Success = true
+	return
+}
+
+// StartTTLWatch poll remaining ttl of key periodically and emit it by returned event name,
+// watching will be stopped automatically once the key expired or deleted
+// @param intervalMs polling interval in milliseconds
+func (b *browserService) StartTTLWatch(server string, db int, k any, intervalMs int) (resp types.JSResp) {
+	key := strutil.DecodeRedisKey(k)
+	// poll on shared client to avoid switching database of browser
+	client, err := Connection().acquireClient(b.ctx, server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
+	interval := time.Duration(intervalMs) * time.Millisecond
+	if intervalMs <= 0 {
+		interval = time.Second
+	} else if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+
+	watchID := fmt.Sprintf("%s|%d|%s", server, db, key)
+	eventName := "ttl:watch:" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+	b.mutex.Lock()
+	if item, ok := b.ttlWatches[watchID]; ok {
+		// stop previous watching of the same key
+		close(item.closeCh)
+	}
+	item := &ttlWatchItem{
+		server:  server,
+		closeCh: make(chan struct{}),
+	}
+	b.ttlWatches[watchID] = item
+	b.mutex.Unlock()
+
+	go func() {
+		defer Connection().releaseClient(server, client)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		type ttlState struct {
+			TTL     int64  `json:"ttl"` // milliseconds, -1 if no expiration
+			Expired bool   `json:"expired"`
+			Error   string `json:"error,omitempty"`
+		}
+		poll := func() bool {
+			ctx, cancel := context.WithTimeout(b.ctx, max(interval, time.Second))
+			defer cancel()
+			var state ttlState
+			ttl, pttlErr := client.Do(ctx, "PTTL", key).Int64()
+			if pttlErr != nil {
+				state.Error = pttlErr.Error()
+			} else {
+				state.TTL = ttl
+				state.Expired = ttl == -2
+			}
+			runtime.EventsEmit(b.ctx, eventName, state)
+			return state.Expired
+		}
+
+		expired := poll()
+		for !expired {
+			select {
+			case <-ticker.C:
+				expired = poll()
+			case <-item.closeCh:
+				return
+			case <-b.ctx.Done():
+				return
+			}
+		}
+
+		// stop watching automatically after key expired
+		b.mutex.Lock()
+		if b.ttlWatches[watchID] == item {
+			delete(b.ttlWatches, watchID)
+		}
+		b.mutex.Unlock()
+	}()
+
 	resp.Success = true
 	resp.Data = struct {
-		Added   []types.HashEntryItem `json:"added,omitempty"`
-		Updated []types.HashEntryItem `json:"updated,omitempty"`
+		EventName string `json:"eventName"`
 	}{
-		Added:   added,
-		Updated: updated,
+		EventName: eventName,
+	}
+	return
+}
+
+// StopTTLWatch stop polling ttl of key
+func (b *browserService) StopTTLWatch(server string, db int, k any) (resp types.JSResp) {
+	watchID := fmt.Sprintf("%s|%d|%s", server, db, strutil.DecodeRedisKey(k))
+	b.mutex.Lock()
+	if item, ok := b.ttlWatches[watchID]; ok {
+		close(item.closeCh)
+		delete(b.ttlWatches, watchID)
+	}
+	b.mutex.Unlock()
+	resp.Success = true
+	return
+}
+
+// stop all ttl watching of server
+func (b *browserService) stopTTLWatches(server string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for watchID, item := range b.ttlWatches {
+		if item.server == server {
+			close(item.closeCh)
+			delete(b.ttlWatches, watchID)
+		}
 	}
-	return
 }
 
-// AddListItem add item to list or remove from it
-func (b *browserService) AddListItem(server string, db int, k any, action int, items []any) (resp types.JSResp) {
+// SetTTLByPattern set the same ttl to all keys matched by pattern, persist keys if ttl < 0
+// @return count of affected keys
+func (b *browserService) SetTTLByPattern(server string, db int, pattern string, ttl int64) (resp types.JSResp) {
+	defer Audit().record(server, db, "ttl_pattern", pattern, fmt.Sprintf("%d seconds", ttl), &resp)
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -1565,29 +4680,52 @@ func (b *browserService) AddListItem(server string, db int, k any, action int, i
 	}
 
 	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(k)
-	var leftPush, rightPush []types.ListEntryItem
-	switch action {
-	case 0:
-		// push to head
-		slices.Reverse(items)
-		_, err = client.LPush(ctx, key, items...).Result()
-		for i := len(items) - 1; i >= 0; i-- {
-			leftPush = append(leftPush, types.ListEntryItem{
-				Value:        items[i],
-				DisplayValue: "", // TODO: convert to display value
-			})
-		}
-	default:
-		// append to tail
-		_, err = client.RPush(ctx, key, items...).Result()
-		for _, it := range items {
-			rightPush = append(rightPush, types.ListEntryItem{
-				Value:        it,
-				DisplayValue: "", // TODO: convert to display value
-			})
+	var affected atomic.Int64
+	expiration := time.Duration(ttl) * time.Second
+	update := func(ctx context.Context, cli redis.UniversalClient) error {
+		scanSize := int64(Preferences().GetScanSize())
+		var cursor uint64
+		for {
+			loadedKeys, nextCursor, scanErr := cli.Scan(ctx, cursor, pattern, scanSize).Result()
+			if scanErr != nil {
+				return scanErr
+			}
+			cursor = nextCursor
+
+			if len(loadedKeys) > 0 {
+				pipe := cli.Pipeline()
+				cmds := make([]*redis.BoolCmd, len(loadedKeys))
+				for i, k := range loadedKeys {
+					if ttl < 0 {
+						cmds[i] = pipe.Persist(ctx, k)
+					} else {
+						cmds[i] = pipe.Expire(ctx, k, expiration)
+					}
+				}
+				if _, execErr := pipe.Exec(ctx); execErr != nil {
+					return execErr
+				}
+				for _, cmd := range cmds {
+					if cmd.Val() {
+						affected.Add(1)
+					}
+				}
+			}
+
+			if cursor == 0 {
+				return nil
+			}
 		}
 	}
+
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return update(ctx, cli)
+		})
+	} else {
+		err = update(ctx, client)
+	}
 	if err != nil {
 		resp.Msg = err.Error()
 		return
@@ -1595,81 +4733,100 @@ func (b *browserService) AddListItem(server string, db int, k any, action int, i
 
 	resp.Success = true
 	resp.Data = struct {
-		Left  []types.ListEntryItem `json:"left,omitempty"`
-		Right []types.ListEntryItem `json:"right,omitempty"`
+		Affected int64 `json:"affected"`
 	}{
-		Left:  leftPush,
-		Right: rightPush,
+		Affected: affected.Load(),
 	}
 	return
 }
 
-// SetListItem update or remove list item by index
-func (b *browserService) SetListItem(param types.SetListParam) (resp types.JSResp) {
-	item, err := b.getRedisClient(param.Server, param.DB)
+// BatchSetTTL batch set ttl
+func (b *browserService) BatchSetTTL(server string, db int, ks []any, ttl int64, serialNo string) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(param.Key)
-	str := strutil.DecodeRedisKey(param.Value)
-	index := int64(param.Index)
-	var replaced, removed []types.ListReplaceItem
-	if len(str) <= 0 {
-		// remove from list
-		err = client.LSet(ctx, key, index, "---VALUE_REMOVED_BY_TINY_RDM---").Err()
-		if err != nil {
-			resp.Msg = err.Error()
-			return
-		}
+	//cancelEvent := "ttling:stop:" + serialNo
+	//runtime.EventsOnce(ctx, cancelEvent, func(data ...any) {
+	//	cancelFunc()
+	//})
+	//processEvent := "ttling:" + serialNo
+	total := len(ks)
+	var failed, updated atomic.Int64
+	var canceled bool
 
-		err = client.LRem(ctx, key, 1, "---VALUE_REMOVED_BY_TINY_RDM---").Err()
-		if err != nil {
-			resp.Msg = err.Error()
-			return
+	expiration := time.Now().Add(time.Duration(ttl) * time.Second)
+	del := func(ctx context.Context, cli redis.UniversalClient) error {
+		startTime := time.Now().Add(-10 * time.Second)
+		for i, k := range ks {
+			// emit progress per second
+			//param := map[string]any{
+			//	"total":      total,
+			//	"progress":   i + 1,
+			//	"processing": k,
+			//}
+			if i >= total-1 || time.Now().Sub(startTime).Milliseconds() > 100 {
+				startTime = time.Now()
+				//runtime.EventsEmit(ctx, processEvent, param)
+				// do some sleep to prevent blocking the Redis server
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			key := strutil.DecodeRedisKey(k)
+			var expErr error
+			if ttl < 0 {
+				expErr = cli.Persist(ctx, key).Err()
+			} else {
+				expErr = cli.ExpireAt(ctx, key, expiration).Err()
+			}
+			if err != nil {
+				failed.Add(1)
+			} else {
+				// save deleted key
+				updated.Add(1)
+			}
+			if errors.Is(expErr, context.Canceled) || canceled {
+				canceled = true
+				break
+			}
 		}
-		removed = append(removed, types.ListReplaceItem{
-			Index: param.Index,
+		return nil
+	}
+
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return del(ctx, cli)
 		})
 	} else {
-		// replace index value
-		var saveStr string
-		decoder := Preferences().GetDecoder()
-		if saveStr, err = convutil.SaveAs(str, param.Format, param.Decode, decoder); err != nil {
-			resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
-			return
-		}
-		err = client.LSet(ctx, key, index, saveStr).Err()
-		if err != nil {
-			resp.Msg = err.Error()
-			return
-		}
-		var displayStr string
-		if len(param.RetDecode) > 0 && len(param.RetFormat) > 0 {
-			displayStr, _, _ = convutil.ConvertTo(saveStr, param.RetDecode, param.RetFormat, decoder)
-		}
-		replaced = append(replaced, types.ListReplaceItem{
-			Index:        param.Index,
-			Value:        saveStr,
-			DisplayValue: displayStr,
-		})
+		err = del(ctx, client)
 	}
 
+	//runtime.EventsOff(ctx, cancelEvent)
 	resp.Success = true
 	resp.Data = struct {
-		Removed  []types.ListReplaceItem `json:"removed,omitempty"`
-		Replaced []types.ListReplaceItem `json:"replaced,omitempty"`
+		Canceled bool  `json:"canceled"`
+		Updated  int64 `json:"updated"`
+		Failed   int64 `json:"failed"`
 	}{
-		Removed:  removed,
-		Replaced: replaced,
+		Canceled: canceled,
+		Updated:  updated.Load(),
+		Failed:   failed.Load(),
 	}
 	return
 }
 
-// SetSetItem add members to set or remove from set
-func (b *browserService) SetSetItem(server string, db int, k any, remove bool, members []any) (resp types.JSResp) {
+// DeleteKey remove redis key
+// @param waitReplicas wait for deletion acknowledged by replicas if > 0, only for single key
+// @param waitTimeout timeout of waiting in milliseconds
+func (b *browserService) DeleteKey(server string, db int, k any, async bool, waitReplicas int, waitTimeout int64) (resp types.JSResp) {
+	defer Audit().record(server, db, "delete", strutil.DecodeRedisKey(k), "", &resp)
+
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -1678,361 +4835,535 @@ func (b *browserService) SetSetItem(server string, db int, k any, remove bool, m
 
 	client, ctx := item.client, item.ctx
 	key := strutil.DecodeRedisKey(k)
-	var added, removed []types.SetEntryItem
-	var affected int64
-	if remove {
-		for _, member := range members {
-			if affected, _ = client.SRem(ctx, key, member).Result(); affected > 0 {
-				removed = append(removed, types.SetEntryItem{
-					Value: member,
-				})
+	var deletedKeys []string
+	var conn *redis.Conn
+	if strings.HasSuffix(key, "*") {
+		// delete by prefix
+		var mutex sync.Mutex
+		supportUnlink := true
+		del := func(ctx context.Context, cli redis.UniversalClient) error {
+			handleDel := func(ks []string) error {
+				var delErr error
+				if async && supportUnlink {
+					if delErr = cli.Unlink(ctx, ks...).Err(); delErr != nil {
+						supportUnlink = false
+						// not support unlink? try del command
+						delErr = cli.Del(ctx, ks...).Err()
+					}
+				} else {
+					delErr = cli.Del(ctx, ks...).Err()
+				}
+
+				mutex.Lock()
+				deletedKeys = append(deletedKeys, ks...)
+				mutex.Unlock()
+
+				return delErr
+			}
+
+			scanSize := int64(Preferences().GetScanSize())
+			iter := cli.Scan(ctx, 0, key, scanSize).Iterator()
+			resultKeys := make([]string, 0, 100)
+			for iter.Next(ctx) {
+				resultKeys = append(resultKeys, iter.Val())
+				if len(resultKeys) >= 20 {
+					handleDel(resultKeys)
+					resultKeys = resultKeys[:0:cap(resultKeys)]
+				}
+			}
+
+			if len(resultKeys) > 0 {
+				handleDel(resultKeys)
 			}
+			return nil
+		}
+
+		if cluster, ok := client.(*redis.ClusterClient); ok {
+			// cluster mode
+			err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+				return del(ctx, cli)
+			})
+		} else {
+			err = del(ctx, client)
+		}
+
+		if err != nil {
+			resp.Msg = err.Error()
+			return
 		}
 	} else {
-		for _, member := range members {
-			if affected, _ = client.SAdd(ctx, key, member).Result(); affected > 0 {
-				added = append(added, types.SetEntryItem{
-					Value:        member,
-					DisplayValue: "", // TODO: convert to display value
-				})
+		// delete key only
+		var delClient redis.Cmdable = client
+		if waitReplicas > 0 {
+			if conn, err = b.dedicatedConn(ctx, server, client, key); err != nil {
+				resp.Msg = err.Error()
+				return
 			}
+			defer conn.Close()
+			delClient = conn
 		}
+		if async {
+			if err = delClient.Unlink(ctx, key).Err(); err != nil {
+				if err = delClient.Del(ctx, key).Err(); err != nil {
+					resp.Msg = err.Error()
+					return
+				}
+			}
+		} else {
+			if err = delClient.Del(ctx, key).Err(); err != nil {
+				resp.Msg = err.Error()
+				return
+			}
+		}
+		deletedKeys = append(deletedKeys, key)
 	}
-	if err != nil {
-		resp.Msg = err.Error()
-		return
-	}
-
-	resp.Success = true
-	resp.Data = struct {
-		Added    []types.SetEntryItem `json:"added,omitempty"`
-		Removed  []types.SetEntryItem `json:"removed,omitempty"`
-		Affected int64                `json:"affected"`
-	}{
-		Added:    added,
-		Removed:  removed,
-		Affected: affected,
-	}
+
+	resp.Success = true
+	respData := map[string]any{
+		"deleted":     deletedKeys,
+		"deleteCount": len(deletedKeys),
+	}
+	if conn != nil {
+		respData["acked"], respData["warning"] = b.waitReplicas(ctx, conn, waitReplicas, waitTimeout)
+	}
+	resp.Data = respData
 	return
 }
 
-// UpdateSetItem replace member of set
-func (b *browserService) UpdateSetItem(param types.SetSetParam) (resp types.JSResp) {
-	item, err := b.getRedisClient(param.Server, param.DB)
+// DeleteOneKey delete one key
+func (b *browserService) DeleteOneKey(server string, db int, k any) (resp types.JSResp) {
+	defer Audit().record(server, db, "delete", strutil.DecodeRedisKey(k), "", &resp)
+
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
 	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(param.Key)
-	var added, removed []types.SetEntryItem
-	var affect int64
-	// remove old value
-	str := strutil.DecodeRedisKey(param.Value)
-	if affect, _ = client.SRem(ctx, key, str).Result(); affect > 0 {
-		removed = append(removed, types.SetEntryItem{
-			Value: str,
+	key := strutil.DecodeRedisKey(k)
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return cli.Del(ctx, key).Err()
 		})
+	} else {
+		err = client.Del(ctx, key).Err()
 	}
 
-	// insert new value
-	str = strutil.DecodeRedisKey(param.NewValue)
-	decoder := Preferences().GetDecoder()
-	var saveStr string
-	if saveStr, err = convutil.SaveAs(str, param.Format, param.Decode, decoder); err != nil {
-		resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
-		return
-	}
-	if affect, _ = client.SAdd(ctx, key, saveStr).Result(); affect > 0 {
-		// add new item
-		var displayStr string
-		if len(param.RetDecode) > 0 && len(param.RetFormat) > 0 {
-			displayStr, _, _ = convutil.ConvertTo(saveStr, param.RetDecode, param.RetFormat, decoder)
-		}
-		added = append(added, types.SetEntryItem{
-			Value:        saveStr,
-			DisplayValue: displayStr,
-		})
-	}
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
 
 	resp.Success = true
-	resp.Data = struct {
-		Added   []types.SetEntryItem `json:"added,omitempty"`
-		Removed []types.SetEntryItem `json:"removed,omitempty"`
-	}{
-		Added:   added,
-		Removed: removed,
-	}
 	return
 }
 
-// UpdateZSetValue update value of sorted set member
-func (b *browserService) UpdateZSetValue(param types.SetZSetParam) (resp types.JSResp) {
-	item, err := b.getRedisClient(param.Server, param.DB)
+// DeleteKeys delete keys sync with notification
+func (b *browserService) DeleteKeys(server string, db int, ks []any, serialNo string) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(param.Key)
-	val, newVal := strutil.DecodeRedisKey(param.Value), strutil.DecodeRedisKey(param.NewValue)
-	var added, updated, removed []types.ZSetEntryItem
-	var replaced []types.ZSetReplaceItem
-	var affect int64
-	decoder := Preferences().GetDecoder()
-	if len(newVal) <= 0 {
-		// no new value, delete value
-		if affect, err = client.ZRem(ctx, key, val).Result(); affect > 0 {
-			//removed = append(removed, val)
-			removed = append(removed, types.ZSetEntryItem{
-				Value: val,
-			})
-		}
-	} else {
-		var saveVal string
-		if saveVal, err = convutil.SaveAs(newVal, param.Format, param.Decode, decoder); err != nil {
-			resp.Msg = fmt.Sprintf(`save to type "%s" fail: %s`, param.Format, err.Error())
-			return
-		}
-
-		if saveVal == val {
-			affect, err = client.ZAdd(ctx, key, redis.Z{
-				Score:  param.Score,
-				Member: saveVal,
-			}).Result()
-			displayValue, _, _ := convutil.ConvertTo(val, param.RetDecode, param.RetFormat, decoder)
-			if affect > 0 {
-				// add new item
-				added = append(added, types.ZSetEntryItem{
-					Score:        param.Score,
-					Value:        val,
-					DisplayValue: displayValue,
-				})
-			} else {
-				// update score only
-				updated = append(updated, types.ZSetEntryItem{
-					Score:        param.Score,
-					Value:        val,
-					DisplayValue: displayValue,
-				})
+	cancelEvent := "delete:stop:" + serialNo
+	cancelStopEvent := runtime.EventsOnce(ctx, cancelEvent, func(data ...any) {
+		cancelFunc()
+	})
+	total := len(ks)
+	var canceled bool
+	var deletedKeys = make([]any, 0, total)
+	var mutex sync.Mutex
+	del := func(ctx context.Context, cli redis.UniversalClient) error {
+		const batchSize = 1000
+		for i := 0; i < total; i += batchSize {
+			pipe := cli.Pipeline()
+			for j := 0; j < batchSize; j++ {
+				if i+j < total {
+					pipe.Del(ctx, strutil.DecodeRedisKey(ks[i+j]))
+				}
 			}
-		} else {
-			// remove old value and add new one
-			_, err = client.ZRem(ctx, key, val).Result()
-			if err != nil {
-				resp.Msg = err.Error()
-				return
+			cmders, delErr := pipe.Exec(ctx)
+			for j, cmder := range cmders {
+				if cmder.(*redis.IntCmd).Val() == 1 {
+					// save deleted key
+					mutex.Lock()
+					deletedKeys = append(deletedKeys, ks[i+j])
+					mutex.Unlock()
+				}
 			}
-
-			affect, err = client.ZAdd(ctx, key, redis.Z{
-				Score:  param.Score,
-				Member: saveVal,
-			}).Result()
-			displayValue, _, _ := convutil.ConvertTo(saveVal, param.RetDecode, param.RetFormat, decoder)
-			if affect <= 0 {
-				// no new value added, just update exists item
-				removed = append(removed, types.ZSetEntryItem{
-					Value: val,
-				})
-				updated = append(updated, types.ZSetEntryItem{
-					Score:        param.Score,
-					Value:        saveVal,
-					DisplayValue: displayValue,
-				})
-			} else {
-				// add new field
-				replaced = append(replaced, types.ZSetReplaceItem{
-					Score:        param.Score,
-					Value:        val,
-					NewValue:     saveVal,
-					DisplayValue: displayValue,
-				})
+			if errors.Is(delErr, context.Canceled) || canceled {
+				canceled = true
+				break
 			}
 		}
+		return nil
 	}
-	if err != nil {
-		resp.Msg = err.Error()
-		return
+
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return del(ctx, cli)
+		})
+	} else {
+		err = del(ctx, client)
 	}
 
+	cancelStopEvent()
 	resp.Success = true
+	Audit().recordKeys(server, db, "delete", sliceutil.Map(deletedKeys, func(i int) string {
+		return strutil.DecodeRedisKey(deletedKeys[i])
+	}), "", &resp)
 	resp.Data = struct {
-		Added    []types.ZSetEntryItem   `json:"added,omitempty"`
-		Updated  []types.ZSetEntryItem   `json:"updated,omitempty"`
-		Replaced []types.ZSetReplaceItem `json:"replaced,omitempty"`
-		Removed  []types.ZSetEntryItem   `json:"removed,omitempty"`
+		Canceled bool `json:"canceled"`
+		Deleted  any  `json:"deleted"`
+		Failed   int  `json:"failed"`
 	}{
-		Added:    added,
-		Updated:  updated,
-		Replaced: replaced,
-		Removed:  removed,
+		Canceled: canceled,
+		Deleted:  deletedKeys,
+		Failed:   len(ks) - len(deletedKeys),
 	}
 	return
 }
 
-// AddZSetValue add item to sorted set
-func (b *browserService) AddZSetValue(server string, db int, k any, action int, valueScore map[string]float64) (resp types.JSResp) {
+// DeleteKeysByPattern delete keys by pattern, matched keys are unlinked in batches while scanning
+// @param dryRun only count matched keys without deleting
+// @param serialNo identify of this deleting, progress will be emitted by event "deleting:<serialNo>"
+func (b *browserService) DeleteKeysByPattern(server string, db int, pattern string, dryRun bool, serialNo string) (resp types.JSResp) {
+	var deletedCount int
+	defer func() {
+		if !dryRun {
+			Audit().record(server, db, "delete_pattern", pattern, fmt.Sprintf("%d keys deleted", deletedCount), &resp)
+		}
+	}()
+
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(k)
+	b.mutex.Lock()
+	b.deleting[serialNo] = cancelFunc
+	b.mutex.Unlock()
+	defer func() {
+		b.mutex.Lock()
+		delete(b.deleting, serialNo)
+		b.mutex.Unlock()
+	}()
+	cancelStopEvent := runtime.EventsOnce(ctx, "delete:stop:"+serialNo, func(data ...any) {
+		cancelFunc()
+	})
+	defer cancelStopEvent()
 
-	var added, updated []types.ZSetEntryItem
-	switch action {
-	case 1:
-		// ignore duplicated fields
-		for m, s := range valueScore {
-			if affect, _ := client.ZAddNX(ctx, key, redis.Z{Score: s, Member: m}).Result(); affect > 0 {
-				added = append(added, types.ZSetEntryItem{
-					Score:        s,
-					Value:        m,
-					DisplayValue: "", // TODO: convert to display value
-				})
+	processEvent := "deleting:" + serialNo
+	var matched, failed int64
+	var deletedKeys = make([]any, 0)
+	var mutex sync.Mutex
+	lastEmit := time.Now()
+	emitProgress := func() {
+		runtime.EventsEmit(ctx, processEvent, map[string]any{
+			"matched": matched,
+			"deleted": len(deletedKeys),
+			"failed":  failed,
+		})
+	}
+	del := func(ctx context.Context, cli redis.UniversalClient) error {
+		scanSize := int64(Preferences().GetScanSize())
+		var cursor uint64
+		for {
+			loadedKeys, nextCursor, scanErr := cli.Scan(ctx, cursor, pattern, scanSize).Result()
+			if scanErr != nil {
+				return scanErr
 			}
-		}
-	default:
-		// overwrite duplicated fields
-		for m, s := range valueScore {
-			if affect, _ := client.ZAdd(ctx, key, redis.Z{Score: s, Member: m}).Result(); affect > 0 {
-				added = append(added, types.ZSetEntryItem{
-					Score:        s,
-					Value:        m,
-					DisplayValue: "", // TODO: convert to display value
-				})
-			} else {
-				updated = append(updated, types.ZSetEntryItem{
-					Score:        s,
-					Value:        m,
-					DisplayValue: "", // TODO: convert to display value
-				})
+			cursor = nextCursor
+
+			var cmders []redis.Cmder
+			if len(loadedKeys) > 0 && !dryRun {
+				pipe := cli.Pipeline()
+				for _, k := range loadedKeys {
+					pipe.Unlink(ctx, k)
+				}
+				var delErr error
+				if cmders, delErr = pipe.Exec(ctx); errors.Is(delErr, context.Canceled) {
+					return delErr
+				}
+			}
+
+			mutex.Lock()
+			matched += int64(len(loadedKeys))
+			for i, cmder := range cmders {
+				if cmder.(*redis.IntCmd).Val() == 1 {
+					deletedKeys = append(deletedKeys, strutil.EncodeRedisKey(loadedKeys[i]))
+				} else {
+					failed += 1
+				}
+			}
+			if time.Since(lastEmit).Milliseconds() > 100 {
+				lastEmit = time.Now()
+				emitProgress()
+			}
+			mutex.Unlock()
+
+			if cursor == 0 {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
 		}
 	}
-	if err != nil {
+
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode, keys scanned from a master are unlinked on the same node
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return del(ctx, cli)
+		})
+	} else {
+		err = del(ctx, client)
+	}
+	emitProgress()
+	deletedCount = len(deletedKeys)
+
+	canceled := errors.Is(err, context.Canceled)
+	if err != nil && !canceled {
 		resp.Msg = err.Error()
 		return
 	}
 
 	resp.Success = true
 	resp.Data = struct {
-		Added   []types.ZSetEntryItem `json:"added,omitempty"`
-		Updated []types.ZSetEntryItem `json:"updated,omitempty"`
+		Canceled bool  `json:"canceled"`
+		DryRun   bool  `json:"dryRun"`
+		Matched  int64 `json:"matched"`
+		Deleted  any   `json:"deleted"`
+		Failed   int64 `json:"failed"`
 	}{
-		Added:   added,
-		Updated: updated,
+		Canceled: canceled,
+		DryRun:   dryRun,
+		Matched:  matched,
+		Deleted:  deletedKeys,
+		Failed:   failed,
 	}
 	return
 }
 
-// AddStreamValue add stream field
-func (b *browserService) AddStreamValue(server string, db int, k any, ID string, fieldItems []any) (resp types.JSResp) {
+// StopDeleteKeysByPattern abort a running deleting started by DeleteKeysByPattern
+func (b *browserService) StopDeleteKeysByPattern(serialNo string) (resp types.JSResp) {
+	b.mutex.Lock()
+	cancelFunc, ok := b.deleting[serialNo]
+	b.mutex.Unlock()
+	if !ok {
+		resp.Msg = "no deleting in progress"
+		return
+	}
+	cancelFunc()
+	resp.Success = true
+	return
+}
+
+// ExportKey export keys
+func (b *browserService) ExportKey(server string, db int, ks []any, path string, includeExpire bool) (resp types.JSResp) {
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(k)
-	var updateID string
-	updateID, err = client.XAdd(ctx, &redis.XAddArgs{
-		Stream: key,
-		ID:     ID,
-		Values: fieldItems,
-	}).Result()
+	file, err := os.Create(path)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer file.Close()
 
-	updateValues := make(map[string]any, len(fieldItems)/2)
-	for i := 0; i < len(fieldItems)/2; i += 2 {
-		updateValues[fieldItems[i].(string)] = fieldItems[i+1]
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	cancelStopEvent := runtime.EventsOnce(ctx, "export:stop:"+path, func(data ...any) {
+		cancelFunc()
+	})
+	processEvent := "exporting:" + path
+	total := len(ks)
+	var exported, failed int64
+	var canceled bool
+	startTime := time.Now().Add(-10 * time.Second)
+	for i, k := range ks {
+		if i >= total-1 || time.Now().Sub(startTime).Milliseconds() > 100 {
+			startTime = time.Now()
+			param := map[string]any{
+				"total":      total,
+				"progress":   i + 1,
+				"processing": k,
+			}
+			runtime.EventsEmit(ctx, processEvent, param)
+		}
+
+		key := strutil.DecodeRedisKey(k)
+		content, dumpErr := client.Dump(ctx, key).Bytes()
+		if errors.Is(dumpErr, context.Canceled) || canceled {
+			canceled = true
+			break
+		}
+		record := []string{hex.EncodeToString([]byte(key)), hex.EncodeToString(content)}
+		if includeExpire {
+			if dur, ttlErr := client.PTTL(ctx, key).Result(); ttlErr == nil && dur > 0 {
+				record = append(record, strconv.FormatInt(time.Now().Add(dur).UnixMilli(), 10))
+			} else {
+				record = append(record, "-1")
+			}
+		}
+		if err = writer.Write(record); err != nil {
+			failed += 1
+		} else {
+			exported += 1
+		}
 	}
-	vb, _ := json.Marshal(updateValues)
-	displayValue, _, _ := convutil.ConvertTo(string(vb), types.DECODE_NONE, types.FORMAT_JSON, Preferences().GetDecoder())
 
+	cancelStopEvent()
 	resp.Success = true
 	resp.Data = struct {
-		Added []types.StreamEntryItem `json:"added,omitempty"`
+		Canceled bool  `json:"canceled"`
+		Exported int64 `json:"exported"`
+		Failed   int64 `json:"failed"`
 	}{
-		Added: []types.StreamEntryItem{
-			{
-				ID:           updateID,
-				Value:        updateValues,
-				DisplayValue: displayValue, // TODO: convert to display value
-			},
-		},
+		Canceled: canceled,
+		Exported: exported,
+		Failed:   failed,
 	}
 	return
 }
 
-// RemoveStreamValues remove stream values by id
-func (b *browserService) RemoveStreamValues(server string, db int, k any, IDs []string) (resp types.JSResp) {
+// ImportCSV import data from csv file
+func (b *browserService) ImportCSV(server string, db int, path string, conflict int, ttl int64) (resp types.JSResp) {
+	defer Audit().record(server, db, "import", "", "csv "+path, &resp)
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(k)
-
-	var affected int64
-	affected, err = client.XDel(ctx, key, IDs...).Result()
+	file, err := os.Open(path)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer file.Close()
 
-	resp.Success = true
-	resp.Data = struct {
-		Affected int64 `json:"affected"`
-	}{
-		Affected: affected,
-	}
-	return
-}
+	reader := csv.NewReader(file)
 
-// SetKeyTTL set ttl of key
-func (b *browserService) SetKeyTTL(server string, db int, k any, ttl int64) (resp types.JSResp) {
-	item, err := b.getRedisClient(server, db)
-	if err != nil {
-		resp.Msg = err.Error()
-		return
-	}
+	cancelEvent := "import:stop:" + path
+	cancelStopEvent := runtime.EventsOnce(ctx, cancelEvent, func(data ...any) {
+		cancelFunc()
+	})
+	processEvent := "importing:" + path
+	var line []string
+	var readErr error
+	var key, value []byte
+	var ttlValue time.Duration
+	var imported, ignored int64
+	var canceled bool
+	startTime := time.Now().Add(-10 * time.Second)
+	for {
+		readErr = nil
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(k)
-	if ttl < 0 {
-		if err = client.Persist(ctx, key).Err(); err != nil {
-			resp.Msg = err.Error()
-			return
+		ttlValue = redis.KeepTTL
+		line, readErr = reader.Read()
+		if readErr != nil {
+			break
 		}
-	} else {
-		expiration := time.Duration(ttl) * time.Second
-		if err = client.Expire(ctx, key, expiration).Err(); err != nil {
-			resp.Msg = err.Error()
-			return
+
+		if len(line) < 1 {
+			continue
+		}
+		if key, readErr = hex.DecodeString(line[0]); readErr != nil {
+			continue
+		}
+		if value, readErr = hex.DecodeString(line[1]); readErr != nil {
+			continue
+		}
+		// get ttl
+		if ttl < 0 && len(line) > 2 {
+			// use previous
+			if expire, ttlErr := strconv.ParseInt(line[2], 10, 64); ttlErr == nil && expire > 0 {
+				ttlValue = time.UnixMilli(expire).Sub(time.Now())
+			}
+		} else if ttl > 0 {
+			// custom ttl
+			ttlValue = time.Duration(ttl) * time.Second
+		}
+		if conflict == 0 {
+			readErr = client.RestoreReplace(ctx, string(key), ttlValue, string(value)).Err()
+		} else {
+			keyStr := string(key)
+			// go-redis may crash when batch calling restore
+			// use "exists" to filter first
+			if n, _ := client.Exists(ctx, keyStr).Result(); n <= 0 {
+				readErr = client.Restore(ctx, keyStr, ttlValue, string(value)).Err()
+			} else {
+				readErr = errors.New("key already existed")
+			}
+		}
+		if readErr != nil {
+			// restore fail
+			ignored += 1
+		} else {
+			imported += 1
+		}
+		if errors.Is(readErr, context.Canceled) || canceled {
+			canceled = true
+			break
+		}
+
+		if time.Now().Sub(startTime).Milliseconds() > 100 {
+			startTime = time.Now()
+			param := map[string]any{
+				"imported": imported,
+				"ignored":  ignored,
+				//"processing": string(key),
+			}
+			runtime.EventsEmit(ctx, processEvent, param)
+			// do some sleep to prevent blocking the Redis server
+			time.Sleep(10 * time.Millisecond)
 		}
 	}
 
+	cancelStopEvent()
 	resp.Success = true
+	resp.Data = struct {
+		Canceled bool  `json:"canceled"`
+		Imported int64 `json:"imported"`
+		Ignored  int64 `json:"ignored"`
+	}{
+		Canceled: canceled,
+		Imported: imported,
+		Ignored:  ignored,
+	}
 	return
 }
 
-// BatchSetTTL batch set ttl
-func (b *browserService) BatchSetTTL(server string, db int, ks []any, ttl int64, serialNo string) (resp types.JSResp) {
+const dumpFileHeader = "TINYRDM-DUMP"
+const dumpFileVersion = "1"
+
+// ExportKeys export keys matched by pattern to dump file which can be imported by ImportKeys
+// the file is csv formatted, start with a header record: "TINYRDM-DUMP",version
+// then each record: hex key, hex DUMP value, expire timestamp in milliseconds or -1 if no expiration,
+// idle time in seconds and LFU frequency, empty if not available due to maxmemory-policy
+func (b *browserService) ExportKeys(server string, db int, pattern string, path string) (resp types.JSResp) {
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -2042,520 +5373,1036 @@ func (b *browserService) BatchSetTTL(server string, db int, ks []any, ttl int64,
 	ctx, cancelFunc := context.WithCancel(b.ctx)
 	defer cancelFunc()
 
-	//cancelEvent := "ttling:stop:" + serialNo
-	//runtime.EventsOnce(ctx, cancelEvent, func(data ...any) {
-	//	cancelFunc()
-	//})
-	//processEvent := "ttling:" + serialNo
-	total := len(ks)
-	var failed, updated atomic.Int64
-	var canceled bool
+	file, err := os.Create(path)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	defer file.Close()
 
-	expiration := time.Now().Add(time.Duration(ttl) * time.Second)
-	del := func(ctx context.Context, cli redis.UniversalClient) error {
-		startTime := time.Now().Add(-10 * time.Second)
-		for i, k := range ks {
-			// emit progress per second
-			//param := map[string]any{
-			//	"total":      total,
-			//	"progress":   i + 1,
-			//	"processing": k,
-			//}
-			if i >= total-1 || time.Now().Sub(startTime).Milliseconds() > 100 {
-				startTime = time.Now()
-				//runtime.EventsEmit(ctx, processEvent, param)
-				// do some sleep to prevent blocking the Redis server
-				time.Sleep(10 * time.Millisecond)
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+	if err = writer.Write([]string{dumpFileHeader, dumpFileVersion}); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	cancelStopEvent := runtime.EventsOnce(ctx, "export:stop:"+path, func(data ...any) {
+		cancelFunc()
+	})
+	defer cancelStopEvent()
+	processEvent := "exporting:" + path
+	var exported, failed int64
+	var mutex sync.Mutex
+	startTime := time.Now()
+	export := func(ctx context.Context, cli redis.UniversalClient) error {
+		scanSize := int64(Preferences().GetScanSize())
+		var cursor uint64
+		for {
+			loadedKeys, nextCursor, scanErr := cli.Scan(ctx, cursor, pattern, scanSize).Result()
+			if scanErr != nil {
+				return scanErr
 			}
+			cursor = nextCursor
+
+			if len(loadedKeys) > 0 {
+				pipe := cli.Pipeline()
+				dumpCmds := make([]*redis.StringCmd, len(loadedKeys))
+				ttlCmds := make([]*redis.DurationCmd, len(loadedKeys))
+				idleCmds := make([]*redis.DurationCmd, len(loadedKeys))
+				freqCmds := make([]*redis.IntCmd, len(loadedKeys))
+				for i, k := range loadedKeys {
+					dumpCmds[i] = pipe.Dump(ctx, k)
+					ttlCmds[i] = pipe.PTTL(ctx, k)
+					idleCmds[i] = pipe.ObjectIdleTime(ctx, k)
+					freqCmds[i] = pipe.ObjectFreq(ctx, k)
+				}
+				if _, execErr := pipe.Exec(ctx); errors.Is(execErr, context.Canceled) {
+					return execErr
+				}
 
-			key := strutil.DecodeRedisKey(k)
-			var expErr error
-			if ttl < 0 {
-				expErr = cli.Persist(ctx, key).Err()
-			} else {
-				expErr = cli.ExpireAt(ctx, key, expiration).Err()
+				mutex.Lock()
+				for i, k := range loadedKeys {
+					content, dumpErr := dumpCmds[i].Bytes()
+					if dumpErr != nil {
+						// key may be removed after scanned
+						failed += 1
+						continue
+					}
+					expire := "-1"
+					if dur := ttlCmds[i].Val(); dur > 0 {
+						expire = strconv.FormatInt(time.Now().Add(dur).UnixMilli(), 10)
+					}
+					// only one of IDLETIME and FREQ is available depends on maxmemory-policy
+					var idleTime, freq string
+					if idle, idleErr := idleCmds[i].Result(); idleErr == nil {
+						idleTime = strconv.FormatInt(int64(idle.Seconds()), 10)
+					}
+					if f, freqErr := freqCmds[i].Result(); freqErr == nil {
+						freq = strconv.FormatInt(f, 10)
+					}
+					if writeErr := writer.Write([]string{hex.EncodeToString([]byte(k)), hex.EncodeToString(content), expire, idleTime, freq}); writeErr != nil {
+						mutex.Unlock()
+						return writeErr
+					}
+					exported += 1
+				}
+				if time.Since(startTime).Milliseconds() > 100 {
+					startTime = time.Now()
+					runtime.EventsEmit(ctx, processEvent, map[string]any{
+						"exported": exported,
+						"failed":   failed,
+					})
+				}
+				mutex.Unlock()
 			}
-			if err != nil {
-				failed.Add(1)
-			} else {
-				// save deleted key
-				updated.Add(1)
+
+			if cursor == 0 {
+				return nil
 			}
-			if errors.Is(expErr, context.Canceled) || canceled {
-				canceled = true
-				break
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
 		}
-		return nil
 	}
 
 	if cluster, ok := client.(*redis.ClusterClient); ok {
 		// cluster mode
 		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
-			return del(ctx, cli)
+			return export(ctx, cli)
 		})
 	} else {
-		err = del(ctx, client)
+		err = export(ctx, client)
+	}
+
+	canceled := errors.Is(err, context.Canceled)
+	if err != nil && !canceled {
+		resp.Msg = err.Error()
+		return
 	}
 
-	//runtime.EventsOff(ctx, cancelEvent)
 	resp.Success = true
 	resp.Data = struct {
 		Canceled bool  `json:"canceled"`
-		Updated  int64 `json:"updated"`
+		Exported int64 `json:"exported"`
 		Failed   int64 `json:"failed"`
 	}{
 		Canceled: canceled,
-		Updated:  updated.Load(),
-		Failed:   failed.Load(),
+		Exported: exported,
+		Failed:   failed,
 	}
 	return
 }
 
-// DeleteKey remove redis key
-func (b *browserService) DeleteKey(server string, db int, k any, async bool) (resp types.JSResp) {
+// ImportKeys import keys from dump file exported by ExportKeys
+// @param onConflict how to handle existing key: skip/replace/abort
+// @param keepAccessInfo restore idle time or LFU frequency recorded in dump file, requires Redis 5.0 or later
+func (b *browserService) ImportKeys(server string, db int, path string, onConflict string, keepAccessInfo bool) (resp types.JSResp) {
+	defer Audit().record(server, db, "import", "", "dump "+path, &resp)
+	switch onConflict {
+	case "skip", "replace", "abort":
+	default:
+		resp.Msg = "unknown conflict strategy: " + onConflict
+		return
+	}
+
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
-	key := strutil.DecodeRedisKey(k)
-	var deletedKeys []string
-	if strings.HasSuffix(key, "*") {
-		// delete by prefix
-		var mutex sync.Mutex
-		supportUnlink := true
-		del := func(ctx context.Context, cli redis.UniversalClient) error {
-			handleDel := func(ks []string) error {
-				var delErr error
-				if async && supportUnlink {
-					if delErr = cli.Unlink(ctx, ks...).Err(); delErr != nil {
-						supportUnlink = false
-						// not support unlink? try del command
-						delErr = cli.Del(ctx, ks...).Err()
-					}
-				} else {
-					delErr = cli.Del(ctx, ks...).Err()
-				}
-
-				mutex.Lock()
-				deletedKeys = append(deletedKeys, ks...)
-				mutex.Unlock()
+	file, err := os.Open(path)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	defer file.Close()
 
-				return delErr
-			}
+	// validate header before importing anything
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil || len(header) < 2 || header[0] != dumpFileHeader {
+		resp.Msg = "invalid dump file format"
+		return
+	}
+	if header[1] != dumpFileVersion {
+		resp.Msg = "unsupported dump file version: " + header[1]
+		return
+	}
 
-			scanSize := int64(Preferences().GetScanSize())
-			iter := cli.Scan(ctx, 0, key, scanSize).Iterator()
-			resultKeys := make([]string, 0, 100)
-			for iter.Next(ctx) {
-				resultKeys = append(resultKeys, iter.Val())
-				if len(resultKeys) >= 20 {
-					handleDel(resultKeys)
-					resultKeys = resultKeys[:0:cap(resultKeys)]
+	cancelStopEvent := runtime.EventsOnce(ctx, "import:stop:"+path, func(data ...any) {
+		cancelFunc()
+	})
+	defer cancelStopEvent()
+	processEvent := "importing:" + path
+	var imported, skipped, failed int64
+	var canceled, aborted bool
+	var abortKey string
+	startTime := time.Now()
+	for {
+		line, readErr := reader.Read()
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) {
+				failed += 1
+				if _, ok := readErr.(*csv.ParseError); ok {
+					continue
 				}
 			}
+			break
+		}
 
-			if len(resultKeys) > 0 {
-				handleDel(resultKeys)
+		if len(line) < 3 {
+			failed += 1
+			continue
+		}
+		key, keyErr := hex.DecodeString(line[0])
+		value, valErr := hex.DecodeString(line[1])
+		expire, expErr := strconv.ParseInt(line[2], 10, 64)
+		if keyErr != nil || valErr != nil || expErr != nil {
+			failed += 1
+			continue
+		}
+		var ttl time.Duration
+		if expire > 0 {
+			if ttl = time.UnixMilli(expire).Sub(time.Now()); ttl <= 0 {
+				// already expired
+				skipped += 1
+				continue
 			}
-			return nil
 		}
 
-		if cluster, ok := client.(*redis.ClusterClient); ok {
-			// cluster mode
-			err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
-				return del(ctx, cli)
-			})
-		} else {
-			err = del(ctx, client)
+		var restoreErr error
+		var restored bool
+		if keepAccessInfo && len(line) >= 5 {
+			if args := b.restoreArgs(string(key), ttl, string(value), onConflict == "replace", line[3], line[4]); args != nil {
+				restoreErr = client.Do(ctx, args...).Err()
+				if restoreErr != nil && strings.Contains(strings.ToLower(restoreErr.Error()), "syntax error") {
+					// IDLETIME and FREQ are not supported before Redis 5.0, fallback to plain RESTORE
+					keepAccessInfo = false
+				} else {
+					restored = true
+				}
+			}
 		}
-
-		if err != nil {
-			resp.Msg = err.Error()
-			return
+		if !restored {
+			if onConflict == "replace" {
+				restoreErr = client.RestoreReplace(ctx, string(key), ttl, string(value)).Err()
+			} else {
+				restoreErr = client.Restore(ctx, string(key), ttl, string(value)).Err()
+			}
 		}
-	} else {
-		// delete key only
-		if async {
-			if err = client.Unlink(ctx, key).Err(); err != nil {
-				if err = client.Del(ctx, key).Err(); err != nil {
-					resp.Msg = err.Error()
-					return
+		if errors.Is(restoreErr, context.Canceled) {
+			canceled = true
+			break
+		}
+		if restoreErr != nil {
+			if strings.HasPrefix(restoreErr.Error(), "BUSYKEY") {
+				if onConflict == "abort" {
+					aborted, abortKey = true, string(key)
+					break
 				}
+				skipped += 1
+			} else {
+				failed += 1
 			}
 		} else {
-			if err = client.Del(ctx, key).Err(); err != nil {
-				resp.Msg = err.Error()
-				return
-			}
+			imported += 1
+		}
+
+		if time.Since(startTime).Milliseconds() > 100 {
+			startTime = time.Now()
+			runtime.EventsEmit(ctx, processEvent, map[string]any{
+				"imported": imported,
+				"skipped":  skipped,
+				"failed":   failed,
+			})
 		}
-		deletedKeys = append(deletedKeys, key)
 	}
 
 	resp.Success = true
-	resp.Data = map[string]any{
-		"deleted":     deletedKeys,
-		"deleteCount": len(deletedKeys),
+	resp.Data = struct {
+		Canceled bool   `json:"canceled"`
+		Aborted  bool   `json:"aborted"`
+		AbortKey string `json:"abortKey,omitempty"` // the existing key which caused aborting
+		Imported int64  `json:"imported"`
+		Skipped  int64  `json:"skipped"`
+		Failed   int64  `json:"failed"`
+	}{
+		Canceled: canceled,
+		Aborted:  aborted,
+		AbortKey: abortKey,
+		Imported: imported,
+		Skipped:  skipped,
+		Failed:   failed,
 	}
 	return
 }
 
-// DeleteOneKey delete one key
-func (b *browserService) DeleteOneKey(server string, db int, k any) (resp types.JSResp) {
+// build arguments of RESTORE command with IDLETIME or FREQ, returns nil if neither is recorded
+func (b *browserService) restoreArgs(key string, ttl time.Duration, value string, replace bool, idleTime, freq string) []any {
+	args := []any{"RESTORE", key, ttl.Milliseconds(), value}
+	if replace {
+		args = append(args, "REPLACE")
+	}
+	// IDLETIME and FREQ can not be specified together
+	if idle, err := strconv.ParseInt(idleTime, 10, 64); err == nil && idle >= 0 {
+		return append(args, "IDLETIME", idle)
+	}
+	if f, err := strconv.ParseInt(freq, 10, 64); err == nil && f >= 0 && f <= 255 {
+		return append(args, "FREQ", f)
+	}
+	return nil
+}
+
+const jsonValueVersion = 1
+const jsonBase64Marker = "$base64:"
+
+type jsonKeyValue struct {
+	Version int    `json:"version"`
+	Key     string `json:"key"`
+	Type    string `json:"type"`
+	TTL     int64  `json:"ttl"` // seconds, -1 if no expiration
+	Value   any    `json:"value"`
+}
+
+type jsonStreamEntry struct {
+	ID     string            `json:"id"`
+	Values map[string]string `json:"values"`
+}
+
+// encode binary-unsafe string to base64 with marker
+// string already starts with the marker will be encoded too, to keep decoding unambiguous
+func (b *browserService) encodeJSONString(str string) string {
+	if !utf8.ValidString(str) || strings.HasPrefix(str, jsonBase64Marker) {
+		return jsonBase64Marker + base64.StdEncoding.EncodeToString([]byte(str))
+	}
+	return str
+}
+
+func (b *browserService) decodeJSONString(str string) (string, error) {
+	if strings.HasPrefix(str, jsonBase64Marker) {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(str, jsonBase64Marker))
+		if err != nil {
+			return "", fmt.Errorf("invalid base64 content: %s", err.Error())
+		}
+		return string(decoded), nil
+	}
+	return str, nil
+}
+
+// ExportKeyCSV export elements of collection key to csv file with header record, columns depend on type:
+// index,value for list; value for set; field,value for hash; member,score for zset
+// progress will be emitted by event "exporting:"+path, send "export:stop:"+path to cancel
+func (b *browserService) ExportKeyCSV(server string, db int, k any, path string) (resp types.JSResp) {
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	client := item.client
+	ctx, cancelFunc := context.WithCancel(b.ctx)
+	defer cancelFunc()
 
-	client, ctx := item.client, item.ctx
 	key := strutil.DecodeRedisKey(k)
-	if cluster, ok := client.(*redis.ClusterClient); ok {
-		// cluster mode
-		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
-			return cli.Del(ctx, key).Err()
-		})
-	} else {
-		err = client.Del(ctx, key).Err()
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	var header []string
+	switch keyType {
+	case "list":
+		header = []string{"index", "value"}
+	case "set":
+		header = []string{"value"}
+	case "hash":
+		header = []string{"field", "value"}
+	case "zset":
+		header = []string{"member", "score"}
+	case "none":
+		resp.Msg = "key not exists"
+		return
+	default:
+		resp.Msg = fmt.Sprintf("export \"%s\" type key to csv is not supported", keyType)
+		return
 	}
+	total, _ := b.getKeyLength(ctx, client, key, keyType)
 
+	file, err := os.Create(path)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer file.Close()
 
-	resp.Success = true
-	return
-}
-
-// DeleteKeys delete keys sync with notification
-func (b *browserService) DeleteKeys(server string, db int, ks []any, serialNo string) (resp types.JSResp) {
-	item, err := b.getRedisClient(server, db)
-	if err != nil {
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+	if err = writer.Write(header); err != nil {
 		resp.Msg = err.Error()
 		return
 	}
-	client := item.client
-	ctx, cancelFunc := context.WithCancel(b.ctx)
-	defer cancelFunc()
 
-	cancelEvent := "delete:stop:" + serialNo
-	cancelStopEvent := runtime.EventsOnce(ctx, cancelEvent, func(data ...any) {
+	cancelStopEvent := runtime.EventsOnce(ctx, "export:stop:"+path, func(data ...any) {
 		cancelFunc()
 	})
-	total := len(ks)
-	var canceled bool
-	var deletedKeys = make([]any, 0, total)
-	var mutex sync.Mutex
-	del := func(ctx context.Context, cli redis.UniversalClient) error {
-		const batchSize = 1000
-		for i := 0; i < total; i += batchSize {
-			pipe := cli.Pipeline()
-			for j := 0; j < batchSize; j++ {
-				if i+j < total {
-					pipe.Del(ctx, strutil.DecodeRedisKey(ks[i+j]))
-				}
+	defer cancelStopEvent()
+	processEvent := "exporting:" + path
+	var exported int64
+	startTime := time.Now()
+	writeRecords := func(records [][]string) error {
+		for _, record := range records {
+			if writeErr := writer.Write(record); writeErr != nil {
+				return writeErr
+			}
+		}
+		exported += int64(len(records))
+		if time.Since(startTime).Milliseconds() > 100 {
+			startTime = time.Now()
+			runtime.EventsEmit(ctx, processEvent, map[string]any{
+				"total":    total,
+				"exported": exported,
+			})
+		}
+		return ctx.Err()
+	}
+
+	scanSize := int64(Preferences().GetScanSize())
+	switch keyType {
+	case "list":
+		// load by range to keep the order of elements
+		for start := int64(0); err == nil; start += scanSize {
+			var items []string
+			if items, err = client.LRange(ctx, key, start, start+scanSize-1).Result(); err != nil || len(items) <= 0 {
+				break
+			}
+			records := make([][]string, len(items))
+			for i, val := range items {
+				records[i] = []string{strconv.FormatInt(start+int64(i), 10), val}
+			}
+			err = writeRecords(records)
+		}
+	case "set":
+		var cursor uint64
+		for err == nil {
+			var items []string
+			if items, cursor, err = client.SScan(ctx, key, cursor, "*", scanSize).Result(); err != nil {
+				break
+			}
+			records := make([][]string, len(items))
+			for i, val := range items {
+				records[i] = []string{val}
+			}
+			if err = writeRecords(records); cursor == 0 {
+				break
+			}
+		}
+	case "hash":
+		var cursor uint64
+		for err == nil {
+			var items []string
+			if items, cursor, err = client.HScan(ctx, key, cursor, "*", scanSize).Result(); err != nil {
+				break
 			}
-			cmders, delErr := pipe.Exec(ctx)
-			for j, cmder := range cmders {
-				if cmder.(*redis.IntCmd).Val() == 1 {
-					// save deleted key
-					mutex.Lock()
-					deletedKeys = append(deletedKeys, ks[i+j])
-					mutex.Unlock()
-				}
+			records := make([][]string, 0, len(items)/2)
+			for i := 0; i+1 < len(items); i += 2 {
+				records = append(records, []string{items[i], items[i+1]})
 			}
-			if errors.Is(delErr, context.Canceled) || canceled {
-				canceled = true
+			if err = writeRecords(records); cursor == 0 {
 				break
 			}
 		}
-		return nil
+	case "zset":
+		// load by rank to keep the order of members
+		for start := int64(0); err == nil; start += scanSize {
+			var items []redis.Z
+			if items, err = client.ZRangeWithScores(ctx, key, start, start+scanSize-1).Result(); err != nil || len(items) <= 0 {
+				break
+			}
+			records := make([][]string, len(items))
+			for i, z := range items {
+				member, _ := z.Member.(string)
+				records[i] = []string{member, strconv.FormatFloat(z.Score, 'f', -1, 64)}
+			}
+			err = writeRecords(records)
+		}
 	}
 
-	if cluster, ok := client.(*redis.ClusterClient); ok {
-		// cluster mode
-		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
-			return del(ctx, cli)
-		})
-	} else {
-		err = del(ctx, client)
+	canceled := errors.Is(err, context.Canceled)
+	if err != nil && !canceled {
+		resp.Msg = err.Error()
+		return
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		resp.Msg = err.Error()
+		return
 	}
 
-	cancelStopEvent()
 	resp.Success = true
 	resp.Data = struct {
-		Canceled bool `json:"canceled"`
-		Deleted  any  `json:"deleted"`
-		Failed   int  `json:"failed"`
+		Canceled bool  `json:"canceled"`
+		Total    int64 `json:"total"`
+		Exported int64 `json:"exported"`
 	}{
 		Canceled: canceled,
-		Deleted:  deletedKeys,
-		Failed:   len(ks) - len(deletedKeys),
+		Total:    total,
+		Exported: exported,
 	}
 	return
 }
 
-// DeleteKeysByPattern delete keys by pattern
-func (b *browserService) DeleteKeysByPattern(server string, db int, pattern string) (resp types.JSResp) {
+// ImportKeyFromFile add elements to collection key from csv or json file in batches
+// csv columns are the same as ExportKeyCSV, header record is optional; json should be an array of elements for
+// list and set, object of field and value for hash, object of member and score for zset
+// malformed rows will be skipped and reported with line number of csv or index of json array
+// @param format csv/json
+// @param replace remove the existing key before importing, otherwise append to it
+func (b *browserService) ImportKeyFromFile(server string, db int, k any, keyType, path, format string, replace bool) (resp types.JSResp) {
+	defer Audit().record(server, db, "import", strutil.DecodeRedisKey(k), format+" "+path, &resp)
+	key := strutil.DecodeRedisKey(k)
+	if len(key) <= 0 {
+		resp.Msg = "key is required"
+		return
+	}
+	var header []string
+	switch keyType {
+	case "list":
+		header = []string{"index", "value"}
+	case "set":
+		header = []string{"value"}
+	case "hash":
+		header = []string{"field", "value"}
+	case "zset":
+		header = []string{"member", "score"}
+	default:
+		resp.Msg = fmt.Sprintf("import \"%s\" type key from file is not supported", keyType)
+		return
+	}
+	if format != "csv" && format != "json" {
+		resp.Msg = "unknown file format: " + format
+		return
+	}
+
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
-	client := item.client
-	ctx, cancelFunc := context.WithCancel(b.ctx)
-	defer cancelFunc()
+	client, ctx := item.client, item.ctx
+	if t, typeErr := client.Type(ctx, key).Result(); typeErr == nil && t != "none" && t != keyType && !replace {
+		resp.Msg = fmt.Sprintf("key already exists as \"%s\" type", t)
+		return
+	}
+
+	type badRow struct {
+		Line  int    `json:"line,omitempty"`  // line number of csv, or index of json array start from 1
+		Field string `json:"field,omitempty"` // field or member of json object
+		Error string `json:"error"`
+	}
+	const maxBadRows = 100
+	const batchSize = 1000
+	var badRows []badRow
+	var imported, failed int64
+	reportBad := func(row badRow) {
+		failed += 1
+		if len(badRows) < maxBadRows {
+			badRows = append(badRows, row)
+		}
+	}
+
+	// elements waiting to be written, each is value for list and set, field and value for hash, member and score for zset
+	var batch [][]string
+	cleared := !replace
+	flush := func() error {
+		if len(batch) <= 0 {
+			return nil
+		}
+		_, execErr := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if !cleared {
+				pipe.Del(ctx, key)
+			}
+			switch keyType {
+			case "list", "set":
+				values := make([]any, len(batch))
+				for i, row := range batch {
+					values[i] = row[0]
+				}
+				if keyType == "list" {
+					pipe.RPush(ctx, key, values...)
+				} else {
+					pipe.SAdd(ctx, key, values...)
+				}
+			case "hash":
+				pairs := make([]any, 0, len(batch)*2)
+				for _, row := range batch {
+					pairs = append(pairs, row[0], row[1])
+				}
+				pipe.HSet(ctx, key, pairs...)
+			case "zset":
+				members := make([]redis.Z, len(batch))
+				for i, row := range batch {
+					score, _ := strconv.ParseFloat(row[1], 64)
+					members[i] = redis.Z{Score: score, Member: row[0]}
+				}
+				pipe.ZAdd(ctx, key, members...)
+			}
+			return nil
+		})
+		if execErr != nil {
+			return execErr
+		}
+		cleared = true
+		imported += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	// check one row, row should be value for list and set, or pair for hash and zset
+	checkRow := func(row []string) error {
+		if keyType == "zset" {
+			if _, parseErr := strconv.ParseFloat(row[1], 64); parseErr != nil {
+				return fmt.Errorf("invalid score: %s", row[1])
+			}
+		}
+		return nil
+	}
+	// append row to batch, and write batch if full
+	addRow := func(row []string) error {
+		batch = append(batch, row)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	}
 
-	var ks []any
-	ks, _, err = b.scanKeys(ctx, client, pattern, "", 0, 0)
+	file, err := os.Open(path)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer file.Close()
 
-	total := len(ks)
-	var canceled bool
-	var deletedKeys = make([]any, 0, total)
-	var mutex sync.Mutex
-	del := func(ctx context.Context, cli redis.UniversalClient) error {
-		const batchSize = 1000
-		for i := 0; i < total; i += batchSize {
-			pipe := cli.Pipeline()
-			for j := 0; j < batchSize; j++ {
-				if i+j < total {
-					pipe.Del(ctx, strutil.DecodeRedisKey(ks[i+j]))
+	var writeErr error
+	if format == "csv" {
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		for first := true; writeErr == nil; first = false {
+			record, readErr := reader.Read()
+			if readErr != nil {
+				if errors.Is(readErr, io.EOF) {
+					break
+				}
+				var parseErr *csv.ParseError
+				if errors.As(readErr, &parseErr) {
+					reportBad(badRow{Line: parseErr.Line, Error: parseErr.Err.Error()})
+					continue
 				}
+				writeErr = readErr
+				break
 			}
-			cmders, delErr := pipe.Exec(ctx)
-			for j, cmder := range cmders {
-				if cmder.(*redis.IntCmd).Val() == 1 {
-					// save deleted key
-					mutex.Lock()
-					deletedKeys = append(deletedKeys, ks[i+j])
-					mutex.Unlock()
+			line, _ := reader.FieldPos(0)
+			if first && slices.Equal(record, header) {
+				// skip header record
+				continue
+			}
+
+			var row []string
+			switch keyType {
+			case "list":
+				// index column is optional and ignored
+				if len(record) > 0 && len(record) <= 2 {
+					row = record[len(record)-1:]
+				}
+			case "set":
+				if len(record) == 1 {
+					row = record
+				}
+			case "hash", "zset":
+				if len(record) == 2 {
+					row = record
 				}
 			}
-			if errors.Is(delErr, context.Canceled) || canceled {
-				canceled = true
-				break
+			if row == nil {
+				reportBad(badRow{Line: line, Error: fmt.Sprintf("unexpected %d columns", len(record))})
+				continue
+			}
+			if rowErr := checkRow(row); rowErr != nil {
+				reportBad(badRow{Line: line, Error: rowErr.Error()})
+				continue
 			}
+			writeErr = addRow(row)
 		}
-		return nil
-	}
-
-	if cluster, ok := client.(*redis.ClusterClient); ok {
-		// cluster mode
-		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
-			return del(ctx, cli)
-		})
 	} else {
-		err = del(ctx, client)
+		var content []byte
+		if content, err = io.ReadAll(file); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		// value may be wrapped in file exported by ExportKeyJSON
+		var wrapped struct {
+			Version int             `json:"version"`
+			Type    string          `json:"type"`
+			Value   json.RawMessage `json:"value"`
+		}
+		if json.Unmarshal(content, &wrapped) == nil && wrapped.Version == jsonValueVersion && wrapped.Type == keyType && len(wrapped.Value) > 0 {
+			content = wrapped.Value
+		}
+		// convert json string or number to string
+		toString := func(raw json.RawMessage) (string, error) {
+			var str string
+			if json.Unmarshal(raw, &str) == nil {
+				return b.decodeJSONString(str)
+			}
+			var num json.Number
+			if json.Unmarshal(raw, &num) == nil {
+				return num.String(), nil
+			}
+			return "", errors.New("should be string or number")
+		}
+
+		switch keyType {
+		case "list", "set":
+			var elements []json.RawMessage
+			if err = json.Unmarshal(content, &elements); err != nil {
+				resp.Msg = "invalid json content, array is expected: " + err.Error()
+				return
+			}
+			for i, elem := range elements {
+				val, convErr := toString(elem)
+				if convErr != nil {
+					reportBad(badRow{Line: i + 1, Error: convErr.Error()})
+					continue
+				}
+				if writeErr = addRow([]string{val}); writeErr != nil {
+					break
+				}
+			}
+		case "hash", "zset":
+			var fields map[string]json.RawMessage
+			if err = json.Unmarshal(content, &fields); err != nil {
+				resp.Msg = "invalid json content, object is expected: " + err.Error()
+				return
+			}
+			for field, raw := range fields {
+				name, nameErr := b.decodeJSONString(field)
+				val, convErr := toString(raw)
+				if nameErr != nil || convErr != nil {
+					reportBad(badRow{Field: field, Error: errors.Join(nameErr, convErr).Error()})
+					continue
+				}
+				row := []string{name, val}
+				if rowErr := checkRow(row); rowErr != nil {
+					reportBad(badRow{Field: field, Error: rowErr.Error()})
+					continue
+				}
+				if writeErr = addRow(row); writeErr != nil {
+					break
+				}
+			}
+		}
+	}
+	if writeErr == nil {
+		writeErr = flush()
+	}
+	if writeErr != nil {
+		resp.Msg = writeErr.Error()
+		return
 	}
 
 	resp.Success = true
 	resp.Data = struct {
-		Canceled bool `json:"canceled"`
-		Deleted  any  `json:"deleted"`
-		Failed   int  `json:"failed"`
+		Imported int64    `json:"imported"`
+		Failed   int64    `json:"failed"`
+		BadRows  []badRow `json:"badRows,omitempty"` // first 100 malformed rows
 	}{
-		Canceled: canceled,
-		Deleted:  deletedKeys,
-		Failed:   len(ks) - len(deletedKeys),
+		Imported: imported,
+		Failed:   failed,
+		BadRows:  badRows,
 	}
 	return
 }
 
-// ExportKey export keys
-func (b *browserService) ExportKey(server string, db int, ks []any, path string, includeExpire bool) (resp types.JSResp) {
+// ExportKeyJSON export value of one key to a human-readable json file
+// list and set are saved as array, hash as object, zset as object of member and score, stream as array of entries
+func (b *browserService) ExportKeyJSON(server string, db int, k any, path string) (resp types.JSResp) {
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
-	client := item.client
-	ctx, cancelFunc := context.WithCancel(b.ctx)
-	defer cancelFunc()
 
-	file, err := os.Create(path)
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	keyType, err := client.Type(ctx, key).Result()
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
-	cancelStopEvent := runtime.EventsOnce(ctx, "export:stop:"+path, func(data ...any) {
-		cancelFunc()
-	})
-	processEvent := "exporting:" + path
-	total := len(ks)
-	var exported, failed int64
-	var canceled bool
-	startTime := time.Now().Add(-10 * time.Second)
-	for i, k := range ks {
-		if i >= total-1 || time.Now().Sub(startTime).Milliseconds() > 100 {
-			startTime = time.Now()
-			param := map[string]any{
-				"total":      total,
-				"progress":   i + 1,
-				"processing": k,
-			}
-			runtime.EventsEmit(ctx, processEvent, param)
+	encode := b.encodeJSONString
+	var value any
+	switch keyType {
+	case "none":
+		resp.Msg = "key not exists"
+		return
+	case "string":
+		var str string
+		if str, err = client.Get(ctx, key).Result(); err == nil {
+			value = encode(str)
 		}
-
-		key := strutil.DecodeRedisKey(k)
-		content, dumpErr := client.Dump(ctx, key).Bytes()
-		if errors.Is(dumpErr, context.Canceled) || canceled {
-			canceled = true
-			break
+	case "list":
+		var items []string
+		if items, err = client.LRange(ctx, key, 0, -1).Result(); err == nil {
+			value = sliceutil.Map(items, func(i int) string {
+				return encode(items[i])
+			})
 		}
-		record := []string{hex.EncodeToString([]byte(key)), hex.EncodeToString(content)}
-		if includeExpire {
-			if dur, ttlErr := client.PTTL(ctx, key).Result(); ttlErr == nil && dur > 0 {
-				record = append(record, strconv.FormatInt(time.Now().Add(dur).UnixMilli(), 10))
-			} else {
-				record = append(record, "-1")
+	case "set":
+		var members []string
+		if members, err = client.SMembers(ctx, key).Result(); err == nil {
+			// sort for stable output
+			sort.Strings(members)
+			value = sliceutil.Map(members, func(i int) string {
+				return encode(members[i])
+			})
+		}
+	case "hash":
+		var fields map[string]string
+		if fields, err = client.HGetAll(ctx, key).Result(); err == nil {
+			obj := make(map[string]string, len(fields))
+			for f, v := range fields {
+				obj[encode(f)] = encode(v)
 			}
+			value = obj
 		}
-		if err = writer.Write(record); err != nil {
-			failed += 1
-		} else {
-			exported += 1
+	case "zset":
+		var members []redis.Z
+		if members, err = client.ZRangeWithScores(ctx, key, 0, -1).Result(); err == nil {
+			obj := make(map[string]float64, len(members))
+			for _, z := range members {
+				obj[encode(z.Member.(string))] = z.Score
+			}
+			value = obj
+		}
+	case "stream":
+		var msgs []redis.XMessage
+		if msgs, err = client.XRange(ctx, key, "-", "+").Result(); err == nil {
+			value = sliceutil.Map(msgs, func(i int) jsonStreamEntry {
+				values := make(map[string]string, len(msgs[i].Values))
+				for f, v := range msgs[i].Values {
+					values[encode(f)] = encode(fmt.Sprint(v))
+				}
+				return jsonStreamEntry{
+					ID:     msgs[i].ID,
+					Values: values,
+				}
+			})
 		}
+	default:
+		resp.Msg = "unsupported type: " + keyType
+		return
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
 	}
 
-	cancelStopEvent()
-	resp.Success = true
-	resp.Data = struct {
-		Canceled bool  `json:"canceled"`
-		Exported int64 `json:"exported"`
-		Failed   int64 `json:"failed"`
-	}{
-		Canceled: canceled,
-		Exported: exported,
-		Failed:   failed,
+	ttl := int64(-1)
+	if dur, ttlErr := client.TTL(ctx, key).Result(); ttlErr == nil && dur > 0 {
+		ttl = int64(dur.Seconds())
+	}
+	content, err := json.MarshalIndent(jsonKeyValue{
+		Version: jsonValueVersion,
+		Key:     encode(key),
+		Type:    keyType,
+		TTL:     ttl,
+		Value:   value,
+	}, "", "  ")
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if err = os.WriteFile(path, content, 0644); err != nil {
+		resp.Msg = err.Error()
+		return
 	}
+
+	resp.Success = true
 	return
 }
 
-// ImportCSV import data from csv file
-func (b *browserService) ImportCSV(server string, db int, path string, conflict int, ttl int64) (resp types.JSResp) {
-	item, err := b.getRedisClient(server, db)
+// ImportKeyJSON import key from json file exported by ExportKeyJSON
+// @param replace replace the key if already exists, otherwise abort importing
+func (b *browserService) ImportKeyJSON(server string, db int, path string, replace bool) (resp types.JSResp) {
+	defer Audit().record(server, db, "import", "", "json "+path, &resp)
+	content, err := os.ReadFile(path)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
-	client := item.client
-	ctx, cancelFunc := context.WithCancel(b.ctx)
-	defer cancelFunc()
+	var data struct {
+		jsonKeyValue
+		Value json.RawMessage `json:"value"`
+	}
+	if err = json.Unmarshal(content, &data); err != nil {
+		resp.Msg = "invalid json content: " + err.Error()
+		return
+	}
+	if data.Version != jsonValueVersion {
+		resp.Msg = fmt.Sprintf("unsupported version: %d", data.Version)
+		return
+	}
+	decode := b.decodeJSONString
+	key, err := decode(data.Key)
+	if err != nil || len(key) <= 0 {
+		resp.Msg = "invalid key"
+		return
+	}
 
-	file, err := os.Open(path)
+	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	cancelEvent := "import:stop:" + path
-	cancelStopEvent := runtime.EventsOnce(ctx, cancelEvent, func(data ...any) {
-		cancelFunc()
-	})
-	processEvent := "importing:" + path
-	var line []string
-	var readErr error
-	var key, value []byte
-	var ttlValue time.Duration
-	var imported, ignored int64
-	var canceled bool
-	startTime := time.Now().Add(-10 * time.Second)
-	for {
-		readErr = nil
-
-		ttlValue = redis.KeepTTL
-		line, readErr = reader.Read()
-		if readErr != nil {
-			break
+	client, ctx := item.client, item.ctx
+	if !replace {
+		if n, _ := client.Exists(ctx, key).Result(); n > 0 {
+			resp.Msg = "key already exists"
+			return
 		}
+	}
 
-		if len(line) < 1 {
-			continue
+	// decode value before writing anything
+	var args []any
+	var streamEntries []jsonStreamEntry
+	var str string
+	switch data.Type {
+	case "string":
+		if err = json.Unmarshal(data.Value, &str); err == nil {
+			str, err = decode(str)
 		}
-		if key, readErr = hex.DecodeString(line[0]); readErr != nil {
-			continue
+	case "list", "set":
+		var items []string
+		if err = json.Unmarshal(data.Value, &items); err == nil {
+			for _, it := range items {
+				var decoded string
+				if decoded, err = decode(it); err != nil {
+					break
+				}
+				args = append(args, decoded)
+			}
 		}
-		if value, readErr = hex.DecodeString(line[1]); readErr != nil {
-			continue
+	case "hash":
+		var fields map[string]string
+		if err = json.Unmarshal(data.Value, &fields); err == nil {
+			for f, v := range fields {
+				var decodedField, decodedValue string
+				if decodedField, err = decode(f); err != nil {
+					break
+				}
+				if decodedValue, err = decode(v); err != nil {
+					break
+				}
+				args = append(args, decodedField, decodedValue)
+			}
 		}
-		// get ttl
-		if ttl < 0 && len(line) > 2 {
-			// use previous
-			if expire, ttlErr := strconv.ParseInt(line[2], 10, 64); ttlErr == nil && expire > 0 {
-				ttlValue = time.UnixMilli(expire).Sub(time.Now())
+	case "zset":
+		var members map[string]float64
+		if err = json.Unmarshal(data.Value, &members); err == nil {
+			for m, score := range members {
+				var decoded string
+				if decoded, err = decode(m); err != nil {
+					break
+				}
+				args = append(args, redis.Z{Score: score, Member: decoded})
 			}
-		} else if ttl > 0 {
-			// custom ttl
-			ttlValue = time.Duration(ttl) * time.Second
 		}
-		if conflict == 0 {
-			readErr = client.RestoreReplace(ctx, string(key), ttlValue, string(value)).Err()
-		} else {
-			keyStr := string(key)
-			// go-redis may crash when batch calling restore
-			// use "exists" to filter first
-			if n, _ := client.Exists(ctx, keyStr).Result(); n <= 0 {
-				readErr = client.Restore(ctx, keyStr, ttlValue, string(value)).Err()
-			} else {
-				readErr = errors.New("key already existed")
+	case "stream":
+		if err = json.Unmarshal(data.Value, &streamEntries); err == nil {
+			for i, entry := range streamEntries {
+				values := make(map[string]string, len(entry.Values))
+				for f, v := range entry.Values {
+					var decodedField, decodedValue string
+					if decodedField, err = decode(f); err != nil {
+						break
+					}
+					if decodedValue, err = decode(v); err != nil {
+						break
+					}
+					values[decodedField] = decodedValue
+				}
+				if err != nil {
+					break
+				}
+				streamEntries[i].Values = values
 			}
 		}
-		if readErr != nil {
-			// restore fail
-			ignored += 1
-		} else {
-			imported += 1
+	default:
+		resp.Msg = "unsupported type: " + data.Type
+		return
+	}
+	if err != nil {
+		resp.Msg = "invalid value content: " + err.Error()
+		return
+	}
+
+	pipe := client.TxPipeline()
+	pipe.Del(ctx, key)
+	switch data.Type {
+	case "string":
+		pipe.Set(ctx, key, str, 0)
+	case "list":
+		if len(args) > 0 {
+			pipe.RPush(ctx, key, args...)
 		}
-		if errors.Is(readErr, context.Canceled) || canceled {
-			canceled = true
-			break
+	case "set":
+		if len(args) > 0 {
+			pipe.SAdd(ctx, key, args...)
 		}
-
-		if time.Now().Sub(startTime).Milliseconds() > 100 {
-			startTime = time.Now()
-			param := map[string]any{
-				"imported": imported,
-				"ignored":  ignored,
-				//"processing": string(key),
-			}
-			runtime.EventsEmit(ctx, processEvent, param)
-			// do some sleep to prevent blocking the Redis server
-			time.Sleep(10 * time.Millisecond)
+	case "hash":
+		if len(args) > 0 {
+			pipe.HSet(ctx, key, args...)
+		}
+	case "zset":
+		if len(args) > 0 {
+			pipe.ZAdd(ctx, key, sliceutil.Map(args, func(i int) redis.Z {
+				return args[i].(redis.Z)
+			})...)
+		}
+	case "stream":
+		for _, entry := range streamEntries {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: key,
+				ID:     entry.ID,
+				Values: entry.Values,
+			})
 		}
 	}
+	if data.TTL > 0 {
+		pipe.Expire(ctx, key, time.Duration(data.TTL)*time.Second)
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
 
-	cancelStopEvent()
 	resp.Success = true
 	resp.Data = struct {
-		Canceled bool  `json:"canceled"`
-		Imported int64 `json:"imported"`
-		Ignored  int64 `json:"ignored"`
+		Key  any    `json:"key"`
+		Type string `json:"type"`
 	}{
-		Canceled: canceled,
-		Imported: imported,
-		Ignored:  ignored,
+		Key:  strutil.EncodeRedisKey(key),
+		Type: data.Type,
 	}
 	return
 }
 
-// FlushDB flush database
-func (b *browserService) FlushDB(server string, db int, async bool) (resp types.JSResp) {
+// flush database, confirmation should be checked by caller
+func (b *browserService) flushDB(server string, db int, async bool) (resp types.JSResp) {
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -2603,7 +6450,11 @@ func (b *browserService) FlushDB(server string, db int, async bool) (resp types.
 }
 
 // RenameKey rename key
-func (b *browserService) RenameKey(server string, db int, key, newKey string) (resp types.JSResp) {
+// in cluster mode, the key will be moved by DUMP and RESTORE if new key is in different slot
+// @param overwrite overwrite the new key if already exists
+func (b *browserService) RenameKey(server string, db int, key, newKey string, overwrite bool) (resp types.JSResp) {
+	defer Audit().record(server, db, "rename", key, "to "+newKey, &resp)
+
 	item, err := b.getRedisClient(server, db)
 	if err != nil {
 		resp.Msg = err.Error()
@@ -2611,15 +6462,132 @@ func (b *browserService) RenameKey(server string, db int, key, newKey string) (r
 	}
 
 	client, ctx := item.client, item.ctx
-	if _, ok := client.(*redis.ClusterClient); ok {
-		resp.Msg = "RENAME not support in cluster mode yet"
+	if overwrite {
+		err = client.Rename(ctx, key, newKey).Err()
+	} else {
+		var ok bool
+		if ok, err = client.RenameNX(ctx, key, newKey).Result(); err == nil && !ok {
+			resp.Msg = "new key already exists"
+			return
+		}
+	}
+
+	if err != nil {
+		if _, isCluster := client.(*redis.ClusterClient); isCluster && strings.HasPrefix(err.Error(), "CROSSSLOT") {
+			err = b.moveKey(ctx, client, key, newKey, overwrite)
+		}
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+	}
+
+	resp.Success = true
+	return
+}
+
+// move key value to new key by DUMP and RESTORE, keeping ttl
+func (b *browserService) moveKey(ctx context.Context, client redis.UniversalClient, key, newKey string, overwrite bool) error {
+	if err := b.dumpRestore(ctx, client, client, key, newKey, overwrite); err != nil {
+		return err
+	}
+	return client.Del(ctx, key).Err()
+}
+
+// copy key value from source client to destination client by DUMP and RESTORE, keeping ttl
+func (b *browserService) dumpRestore(ctx context.Context, srcClient, dstClient redis.UniversalClient, key, newKey string, overwrite bool) error {
+	dump, err := srcClient.Dump(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return errors.New("key not exists")
+		}
+		return err
+	}
+	ttl, err := srcClient.PTTL(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if ttl < 0 {
+		// no expiration
+		ttl = 0
+	}
+
+	if overwrite {
+		err = dstClient.RestoreReplace(ctx, newKey, ttl, dump).Err()
+	} else {
+		err = dstClient.Restore(ctx, newKey, ttl, dump).Err()
+		if err != nil && strings.HasPrefix(err.Error(), "BUSYKEY") {
+			return errors.New("new key already exists")
+		}
+	}
+	return err
+}
+
+// CopyKey copy key to another key, which can be in other database or other server
+// COPY is used if copying inside the same server, otherwise fallback to DUMP and RESTORE
+// @param replace replace destination key if exists
+func (b *browserService) CopyKey(server string, srcDB int, srcKey any, dstServer string, dstDB int, dstKey any, replace bool) (resp types.JSResp) {
+	if len(dstServer) <= 0 {
+		dstServer = server
+	}
+	key, newKey := strutil.DecodeRedisKey(srcKey), strutil.DecodeRedisKey(dstKey)
+	defer Audit().record(dstServer, dstDB, "copy", newKey, fmt.Sprintf("from %s db%d %s", server, srcDB, key), &resp)
+	if server == dstServer && srcDB == dstDB && key == newKey {
+		resp.Msg = "source and destination are the same"
 		return
 	}
 
-	if _, err = client.RenameNX(ctx, key, newKey).Result(); err != nil {
+	// borrow shared clients directly to avoid switching database of browser
+	srcClient, err := Connection().acquireClient(b.ctx, server, srcDB)
+	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer Connection().releaseClient(server, srcClient)
+	ctx := b.ctx
+
+	var copied bool
+	if server == dstServer {
+		_, isCluster := srcClient.(*redis.ClusterClient)
+		if isCluster && dstDB > 0 {
+			resp.Msg = "SELECT not supported in cluster mode"
+			return
+		}
+		// COPY is available since redis 6.2
+		var n int64
+		if n, err = srcClient.Copy(ctx, key, newKey, dstDB, replace).Result(); err == nil {
+			if n <= 0 {
+				if exists, _ := srcClient.Exists(ctx, key).Result(); exists <= 0 {
+					resp.Msg = "key not exists"
+				} else {
+					resp.Msg = "new key already exists"
+				}
+				return
+			}
+			copied = true
+		} else if !strings.Contains(strings.ToLower(err.Error()), "unknown command") &&
+			!(isCluster && strings.HasPrefix(err.Error(), "CROSSSLOT")) {
+			resp.Msg = err.Error()
+			return
+		}
+	}
+
+	if !copied {
+		dstClient, err := Connection().acquireClient(b.ctx, dstServer, dstDB)
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		defer Connection().releaseClient(dstServer, dstClient)
+		if err = b.dumpRestore(ctx, srcClient, dstClient, key, newKey, replace); err != nil {
+			if strings.Contains(err.Error(), "payload version") {
+				resp.Msg = "destination server can not restore value dumped by source server of higher version"
+			} else {
+				resp.Msg = err.Error()
+			}
+			return
+		}
+	}
 
 	resp.Success = true
 	return
@@ -2670,7 +6638,7 @@ func (b *browserService) GetSlowLogs(server string, num int64) (resp types.JSRes
 		// cluster mode
 		var mu sync.Mutex
 		err = cluster.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
-			if subLogs, _ := client.SlowLogGet(ctx, num).Result(); len(subLogs) > 0 {
+			if subLogs, _ := cli.SlowLogGet(ctx, num).Result(); len(subLogs) > 0 {
 				mu.Lock()
 				logs = append(logs, subLogs...)
 				mu.Unlock()
@@ -2685,8 +6653,12 @@ func (b *browserService) GetSlowLogs(server string, num int64) (resp types.JSRes
 		return
 	}
 
+	// newest first, the timestamp is in seconds, so compare id if in the same second
 	sort.Slice(logs, func(i, j int) bool {
-		return logs[i].Time.UnixMilli() > logs[j].Time.UnixMilli()
+		if logs[i].Time.Equal(logs[j].Time) {
+			return logs[i].ID > logs[j].ID
+		}
+		return logs[i].Time.After(logs[j].Time)
 	})
 	if len(logs) > int(num) {
 		logs = logs[:num]
@@ -2698,12 +6670,16 @@ func (b *browserService) GetSlowLogs(server string, num int64) (resp types.JSRes
 		if name, e = url.QueryUnescape(logs[i].ClientName); e != nil {
 			name = logs[i].ClientName
 		}
+		// client address and name are absent before redis 4.0
 		return slowLogItem{
+			ID:        logs[i].ID,
 			Timestamp: logs[i].Time.UnixMilli(),
 			Client:    name,
 			Addr:      logs[i].ClientAddr,
 			Cmd:       sliceutil.JoinString(logs[i].Args, " "),
+			Args:      logs[i].Args,
 			Cost:      logs[i].Duration.Milliseconds(),
+			Duration:  logs[i].Duration.Microseconds(),
 		}
 	})
 
@@ -2714,6 +6690,71 @@ func (b *browserService) GetSlowLogs(server string, num int64) (resp types.JSRes
 	return
 }
 
+// ResetSlowLog clean all slow logs
+func (b *browserService) ResetSlowLog(server string) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return cli.Do(ctx, "slowlog", "reset").Err()
+		})
+	} else {
+		err = client.Do(ctx, "slowlog", "reset").Err()
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	return
+}
+
+// parse one line of CLIENT LIST output, fields may be different between redis versions
+// id=3 addr=127.0.0.1:59228 laddr=127.0.0.1:6379 fd=8 name= age=5 idle=0 flags=N db=0 ... cmd=client|list
+func (b *browserService) parseClientInfo(line string) clientInfoItem {
+	info := clientInfoItem{
+		Fields: map[string]string{},
+	}
+	for _, it := range strings.Split(line, " ") {
+		k, v, ok := strings.Cut(it, "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "id":
+			info.ID, _ = strconv.ParseInt(v, 10, 64)
+		case "addr":
+			info.Addr = v
+		case "name":
+			if name, err := url.QueryUnescape(v); err == nil {
+				info.Name = name
+			} else {
+				info.Name = v
+			}
+		case "age":
+			info.Age, _ = strconv.ParseInt(v, 10, 64)
+		case "idle":
+			info.Idle, _ = strconv.ParseInt(v, 10, 64)
+		case "flags":
+			info.Flags = v
+		case "db":
+			info.DB, _ = strconv.Atoi(v)
+		case "cmd":
+			info.Cmd = v
+		default:
+			info.Fields[k] = v
+		}
+	}
+	return info
+}
+
 // GetClientList get all connected client info
 func (b *browserService) GetClientList(server string) (resp types.JSResp) {
 	item, err := b.getRedisClient(server, -1)
@@ -2722,38 +6763,41 @@ func (b *browserService) GetClientList(server string) (resp types.JSResp) {
 		return
 	}
 
-	parseContent := func(content string) []map[string]string {
+	parseContent := func(content string) []clientInfoItem {
 		lines := strings.Split(content, "\n")
-		list := make([]map[string]string, 0, len(lines))
+		list := make([]clientInfoItem, 0, len(lines))
 		for _, line := range lines {
 			line = strings.TrimSpace(line)
 			if len(line) > 0 {
-				items := strings.Split(line, " ")
-				itemKV := map[string]string{}
-				for _, it := range items {
-					kv := strings.SplitN(it, "=", 2)
-					if len(kv) > 1 {
-						itemKV[kv[0]] = kv[1]
-					}
-				}
-				list = append(list, itemKV)
+				list = append(list, b.parseClientInfo(line))
 			}
 		}
 		return list
 	}
 
 	client, ctx := item.client, item.ctx
-	var fullList []map[string]string
+	var fullList []clientInfoItem
 	var mutex sync.Mutex
 	if cluster, ok := client.(*redis.ClusterClient); ok {
-		cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			content, err := cli.ClientList(ctx).Result()
+			if err != nil {
+				return err
+			}
 			mutex.Lock()
 			defer mutex.Unlock()
-			fullList = append(fullList, parseContent(cli.ClientList(ctx).Val())...)
+			fullList = append(fullList, parseContent(content)...)
 			return nil
 		})
 	} else {
-		fullList = append(fullList, parseContent(client.ClientList(ctx).Val())...)
+		var content string
+		if content, err = client.ClientList(ctx).Result(); err == nil {
+			fullList = parseContent(content)
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
 	}
 
 	resp.Success = true
@@ -2762,3 +6806,58 @@ func (b *browserService) GetClientList(server string) (resp types.JSResp) {
 	}
 	return
 }
+
+// KillClient close client connection by id or address("ip:port")
+func (b *browserService) KillClient(server string, target string) (resp types.JSResp) {
+	item, err := b.getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	filter := "ADDR"
+	if _, parseErr := strconv.ParseInt(target, 10, 64); parseErr == nil {
+		filter = "ID"
+	}
+	kill := func(ctx context.Context, cli redis.UniversalClient) (int64, error) {
+		killed, err := cli.ClientKillByFilter(ctx, filter, target).Result()
+		if err != nil && filter == "ADDR" && strings.Contains(strings.ToLower(err.Error()), "syntax") {
+			// old style for redis before 2.8.12
+			if err = cli.ClientKill(ctx, target).Err(); err == nil {
+				killed = 1
+			}
+		}
+		return killed, err
+	}
+
+	client, ctx := item.client, item.ctx
+	var killed atomic.Int64
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// client may connect to any node
+		err = cluster.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
+			n, _ := kill(ctx, cli)
+			killed.Add(n)
+			return nil
+		})
+	} else {
+		var n int64
+		n, err = kill(ctx, client)
+		killed.Add(n)
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if killed.Load() <= 0 {
+		resp.Msg = "no such client"
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Killed int64 `json:"killed"`
+	}{
+		Killed: killed.Load(),
+	}
+	return
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"net"
+	"strings"
+	"testing"
+	"tinyrdm/backend/types"
+)
+
+// fakeKeyHook answers commands of a single key in memory, the key exists
+// when TYPE is queried and expires right after it
+type fakeKeyHook struct {
+	keyType string
+	expired bool
+}
+
+func (h *fakeKeyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, net.ErrClosed
+	}
+}
+
+func (h *fakeKeyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		switch c := cmd.(type) {
+		case *redis.StatusCmd:
+			if strings.ToLower(cmd.Name()) == "type" {
+				c.SetVal(h.keyType)
+			}
+		case *redis.StringCmd:
+			if h.expired {
+				c.SetErr(redis.Nil)
+			} else {
+				c.SetVal("value")
+			}
+		case *redis.StringSliceCmd:
+			if h.expired {
+				c.SetVal([]string{})
+			} else {
+				c.SetVal([]string{"value"})
+			}
+		case *redis.IntCmd:
+			if h.expired {
+				c.SetVal(0)
+			} else {
+				c.SetVal(1)
+			}
+		}
+		return cmd.Err()
+	}
+}
+
+func (h *fakeKeyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.ProcessHook(nil)(ctx, cmd)
+		}
+		return nil
+	}
+}
+
+// put a browser connection of server in advance, so that no connection profile is required
+func seedBrowserClient(t *testing.T, server string, hook redis.Hook) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	client.AddHook(hook)
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	b := Browser()
+	b.mutex.Lock()
+	b.connMap[server] = &connectionItem{
+		client:      client,
+		ctx:         ctx,
+		cancelFunc:  cancelFunc,
+		cursor:      map[int]uint64{},
+		entryCursor: map[int]entryCursor{},
+		stepSize:    10,
+	}
+	b.mutex.Unlock()
+	t.Cleanup(func() {
+		b.mutex.Lock()
+		delete(b.connMap, server)
+		b.mutex.Unlock()
+		cancelFunc()
+		client.Close()
+	})
+}
+
+func TestGetKeyDetailExpired(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	tests := []struct {
+		name    string
+		keyType string
+		expired bool
+	}{
+		{name: "not_exists", keyType: "none"},
+		{name: "string_expired", keyType: "string", expired: true},
+		{name: "list_expired", keyType: "list", expired: true},
+		{name: "string", keyType: "string"},
+		{name: "list", keyType: "list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := "detail_" + tt.name
+			seedBrowserClient(t, server, &fakeKeyHook{keyType: tt.keyType, expired: tt.expired})
+
+			resp := Browser().GetKeyDetail(types.KeyDetailParam{
+				Server: server,
+				Key:    "key",
+				Reset:  true,
+			})
+			if tt.expired || tt.keyType == "none" {
+				if resp.Success || resp.Code != types.RESP_CODE_KEY_NOT_EXISTS {
+					t.Fatalf("expect code %s, got success: %v, code: %s, msg: %s",
+						types.RESP_CODE_KEY_NOT_EXISTS, resp.Success, resp.Code, resp.Msg)
+				}
+			} else if !resp.Success || len(resp.Code) > 0 {
+				t.Fatalf("expect success, got code: %s, msg: %s", resp.Code, resp.Msg)
+			}
+		})
+	}
+}
@@ -6,9 +6,14 @@ import (
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
+	"tinyrdm/backend/consts"
 	"tinyrdm/backend/types"
+	redis2 "tinyrdm/backend/utils/redis"
 	sliceutil "tinyrdm/backend/utils/slice"
 	strutil "tinyrdm/backend/utils/string"
 )
@@ -19,11 +24,53 @@ type cliService struct {
 	mutex      sync.Mutex
 	clients    map[string]redis.UniversalClient
 	selectedDB map[string]int
+	history    map[string][]string               // executed command lines of each server
+	latency    map[string]map[string]*cmdLatency // latency of each command name of each server
+}
+
+// rolling latency of one command, only recent durations are kept for percentile
+type cmdLatency struct {
+	count   int64
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+func (l *cmdLatency) add(d time.Duration) {
+	if l.count <= 0 || d < l.min {
+		l.min = d
+	}
+	if d > l.max {
+		l.max = d
+	}
+	l.count += 1
+	l.total += d
+	if len(l.samples) < consts.DEFAULT_CLI_LATENCY_SAMPLES {
+		l.samples = append(l.samples, d)
+	} else {
+		l.samples[l.next] = d
+		l.next = (l.next + 1) % len(l.samples)
+	}
+}
+
+// get 95th percentile of recent durations
+func (l *cmdLatency) p95() time.Duration {
+	if len(l.samples) <= 0 {
+		return 0
+	}
+	sorted := slices.Clone(l.samples)
+	slices.Sort(sorted)
+	idx := (len(sorted)*95+99)/100 - 1
+	return sorted[max(idx, 0)]
 }
 
 type cliOutput struct {
 	Content []string `json:"content"`          // output content
 	Prompt  string   `json:"prompt,omitempty"` // new line prompt, empty if not ready to input
+
+	Duration float64 `json:"duration,omitempty"` // round-trip of executed command in milliseconds
 }
 
 var cli *cliService
@@ -35,19 +82,135 @@ func Cli() *cliService {
 			cli = &cliService{
 				clients:    map[string]redis.UniversalClient{},
 				selectedDB: map[string]int{},
+				history:    map[string][]string{},
+				latency:    map[string]map[string]*cmdLatency{},
 			}
 		})
 	}
 	return cli
 }
 
+// commands which may cause data loss or service interruption, required confirmation before execute
+var dangerousCommands = map[string][]string{
+	"flushall":  nil,
+	"flushdb":   nil,
+	"shutdown":  nil,
+	"keys":      nil,
+	"debug":     nil,
+	"swapdb":    nil,
+	"replicaof": nil,
+	"slaveof":   nil,
+	"failover":  nil,
+	"cluster":   {"reset", "failover", "forget", "flushslots"},
+	"config":    {"set", "rewrite", "resetstat"},
+	"script":    {"flush"},
+	"function":  {"flush", "delete"},
+	"client":    {"kill"},
+	"acl":       {"deluser"},
+}
+
+// check if command is dangerous
+func (c *cliService) isDangerousCommand(cmds []string) bool {
+	if len(cmds) <= 0 {
+		return false
+	}
+	subs, ok := dangerousCommands[strings.ToLower(cmds[0])]
+	if !ok {
+		return false
+	}
+	if len(subs) <= 0 {
+		return true
+	}
+	return len(cmds) > 1 && slices.Contains(subs, strings.ToLower(cmds[1]))
+}
+
+// reject write command of read-only connection, it's required since the dedicated connection
+// taken by Conn() bypasses the read-only hook of client
+func (c *cliService) checkWritable(server string, cmds []string) error {
+	if !c.isReadOnly(server) {
+		return nil
+	}
+	return c.checkWriteCommand(cmds)
+}
+
+// check if connection profile of server is read-only
+func (c *cliService) isReadOnly(server string) bool {
+	conf := Connection().getConnection(server)
+	return conf != nil && conf.ReadOnly
+}
+
+// get ErrReadOnly if command may modify data
+func (c *cliService) checkWriteCommand(cmds []string) error {
+	args := sliceutil.Map(cmds, func(i int) any {
+		return cmds[i]
+	})
+	if redis2.IsWriteCommand(args) {
+		return redis2.ErrReadOnly
+	}
+	return nil
+}
+
+// record write command to audit log if enabled
+func (c *cliService) recordAudit(server string, db int, cmds []string, err error) {
+	var errMsg string
+	if err != nil && !errors.Is(err, redis.Nil) {
+		errMsg = err.Error()
+	}
+	Audit().recordCommands(server, db, [][]string{cmds}, []string{errMsg})
+}
+
+// append command line to history of server
+func (c *cliService) appendHistory(server, cmd string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	history := append(c.history[server], cmd)
+	if len(history) > consts.DEFAULT_CLI_HISTORY_SIZE {
+		history = history[len(history)-consts.DEFAULT_CLI_HISTORY_SIZE:]
+	}
+	c.history[server] = history
+}
+
+// record round-trip duration of command, new command names are ignored if too many tracked
+func (c *cliService) recordLatency(server, cmd string, d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cmd = strings.ToLower(cmd)
+	stats, ok := c.latency[server]
+	if !ok {
+		stats = map[string]*cmdLatency{}
+		c.latency[server] = stats
+	}
+	l, ok := stats[cmd]
+	if !ok {
+		if len(stats) >= consts.MAX_CLI_LATENCY_COMMANDS {
+			return
+		}
+		l = &cmdLatency{}
+		stats[cmd] = l
+	}
+	l.add(d)
+}
+
+// convert duration to milliseconds
+func toMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
 func (c *cliService) runCommand(server, data string) {
 	if cmds := strutil.SplitCmd(data); len(cmds) > 0 && len(cmds[0]) > 0 {
+		c.appendHistory(server, data)
 		if client, err := c.getRedisClient(server); err == nil {
 			args := sliceutil.Map(cmds, func(i int) any {
 				return cmds[i]
 			})
-			if result, err := client.Do(c.ctx, args...).Result(); err == nil || errors.Is(err, redis.Nil) {
+			start := time.Now()
+			result, err := client.Do(c.ctx, args...).Result()
+			duration := time.Since(start)
+			c.recordLatency(server, cmds[0], duration)
+			c.recordAudit(server, c.selectedDB[server], cmds, err)
+			if err == nil || errors.Is(err, redis.Nil) {
 				if strings.ToLower(cmds[0]) == "select" {
 					// switch database
 					if db, ok := strutil.AnyToInt(cmds[1]); ok {
@@ -55,9 +218,9 @@ func (c *cliService) runCommand(server, data string) {
 					}
 				}
 
-				c.echo(server, strutil.AnyToString(result, "", 0), true)
+				c.echoOutput(server, strutil.AnyToString(result, "", 0), duration)
 			} else {
-				c.echoError(server, err.Error())
+				c.echoOutput(server, "\x1b[31m"+err.Error()+"\x1b[0m", duration)
 			}
 			return
 		}
@@ -76,12 +239,17 @@ func (c *cliService) echo(server, data string, newLineReady bool) {
 	runtime.EventsEmit(c.ctx, "cmd:output:"+server, output)
 }
 
-func (c *cliService) echoReady(server string) {
-	c.echo(server, "", true)
+// echo output of executed command with its duration
+func (c *cliService) echoOutput(server, data string, duration time.Duration) {
+	runtime.EventsEmit(c.ctx, "cmd:output:"+server, cliOutput{
+		Content:  strings.Split(data, "\n"),
+		Prompt:   fmt.Sprintf("%s:db%d> ", server, c.selectedDB[server]),
+		Duration: toMillis(duration),
+	})
 }
 
-func (c *cliService) echoError(server, data string) {
-	c.echo(server, "\x1b[31m"+data+"\x1b[0m", true)
+func (c *cliService) echoReady(server string) {
+	c.echo(server, "", true)
 }
 
 func (c *cliService) getRedisClient(server string) (redis.UniversalClient, error) {
@@ -95,7 +263,7 @@ func (c *cliService) getRedisClient(server string) (redis.UniversalClient, error
 		if conf == nil {
 			return nil, fmt.Errorf("no connection profile named: %s", server)
 		}
-		if client, err = Connection().createRedisClient(conf.ConnectionConfig); err != nil {
+		if client, err = Connection().createRedisClient(c.ctx, conf.ConnectionConfig, "cli"); err != nil {
 			return nil, err
 		}
 		c.clients[server] = client
@@ -134,6 +302,255 @@ func (c *cliService) StartCli(server string, db int) (resp types.JSResp) {
 	return
 }
 
+// RunCommand execute a command line on specified database, reply is formatted like redis-cli
+// @param confirmed dangerous command will be rejected if not confirmed
+func (c *cliService) RunCommand(server string, db int, commandLine string, confirmed bool) (resp types.JSResp) {
+	cmds := strutil.SplitCmd(commandLine)
+	if len(cmds) <= 0 || len(cmds[0]) <= 0 {
+		resp.Msg = "empty command"
+		return
+	}
+	if !confirmed && c.isDangerousCommand(cmds) {
+		resp.Msg = fmt.Sprintf("\"%s\" is a dangerous command, please confirm before executing", strings.ToUpper(cmds[0]))
+		resp.Data = struct {
+			NeedConfirm bool `json:"needConfirm"`
+		}{
+			NeedConfirm: true,
+		}
+		return
+	}
+
+	if err := c.checkWritable(server, cmds); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, err := c.getRedisClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	c.appendHistory(server, commandLine)
+
+	args := sliceutil.Map(cmds, func(i int) any {
+		return cmds[i]
+	})
+	var result any
+	var duration time.Duration
+	if rdb, ok := client.(*redis.Client); ok {
+		// select database on a dedicated connection before executing
+		conn := rdb.Conn()
+		if err = conn.Select(c.ctx, db).Err(); err == nil {
+			cmd := redis.NewCmd(c.ctx, args...)
+			start := time.Now()
+			_ = conn.Process(c.ctx, cmd)
+			duration = time.Since(start)
+			c.recordLatency(server, cmds[0], duration)
+			result, err = cmd.Result()
+		}
+		conn.Close()
+	} else if db > 0 {
+		err = errors.New("SELECT not supported in cluster mode")
+	} else {
+		start := time.Now()
+		result, err = client.Do(c.ctx, args...).Result()
+		duration = time.Since(start)
+		c.recordLatency(server, cmds[0], duration)
+	}
+
+	c.recordAudit(server, db, cmds, err)
+
+	var output string
+	if err == nil || errors.Is(err, redis.Nil) {
+		output = strutil.FormatReply(result)
+	} else {
+		output = strutil.FormatReply(err)
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Output   string  `json:"output"`
+		Duration float64 `json:"duration"` // round-trip in milliseconds
+	}{
+		Output:   output,
+		Duration: toMillis(duration),
+	}
+	return
+}
+
+// ExecPipeline execute multiple commands in one round trip and returns each reply in order
+// @param transactional wrap commands by MULTI/EXEC if true
+func (c *cliService) ExecPipeline(server string, db int, commands [][]string, transactional bool) (resp types.JSResp) {
+	if len(commands) <= 0 {
+		resp.Msg = "empty command"
+		return
+	}
+	readOnly := c.isReadOnly(server)
+	for i, cmds := range commands {
+		if len(cmds) <= 0 || len(cmds[0]) <= 0 {
+			resp.Msg = fmt.Sprintf("empty command at #%d", i+1)
+			return
+		}
+		if c.isDangerousCommand(cmds) {
+			resp.Msg = fmt.Sprintf("\"%s\" is a dangerous command, please execute it separately", strings.ToUpper(cmds[0]))
+			return
+		}
+		if readOnly {
+			if err := c.checkWriteCommand(cmds); err != nil {
+				resp.Msg = fmt.Sprintf("%s: \"%s\" at #%d", err.Error(), strings.ToUpper(cmds[0]), i+1)
+				return
+			}
+		}
+	}
+
+	client, err := c.getRedisClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	var pipe redis.Pipeliner
+	if rdb, ok := client.(*redis.Client); ok {
+		// select database on a dedicated connection before executing
+		conn := rdb.Conn()
+		defer conn.Close()
+		if err = conn.Select(c.ctx, db).Err(); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		if transactional {
+			pipe = conn.TxPipeline()
+		} else {
+			pipe = conn.Pipeline()
+		}
+	} else if db > 0 {
+		resp.Msg = "SELECT not supported in cluster mode"
+		return
+	} else if transactional {
+		pipe = client.TxPipeline()
+	} else {
+		pipe = client.Pipeline()
+	}
+
+	cmdList := make([]*redis.Cmd, 0, len(commands))
+	for _, cmds := range commands {
+		args := sliceutil.Map(cmds, func(i int) any {
+			return cmds[i]
+		})
+		cmdList = append(cmdList, pipe.Do(c.ctx, args...))
+	}
+	start := time.Now()
+	_, err = pipe.Exec(c.ctx)
+	duration := time.Since(start)
+	// EXEC returns nil reply if aborted by WATCH
+	aborted := transactional && errors.Is(err, redis.TxFailedErr)
+
+	type pipelineReply struct {
+		Output string `json:"output"`
+		Error  string `json:"error,omitempty"`
+	}
+	replies := make([]pipelineReply, 0, len(cmdList))
+	errs := make([]string, 0, len(cmdList))
+	for _, cmd := range cmdList {
+		var reply pipelineReply
+		if result, cmdErr := cmd.Result(); cmdErr == nil || errors.Is(cmdErr, redis.Nil) {
+			reply.Output = strutil.FormatReply(result)
+		} else {
+			reply.Output = strutil.FormatReply(cmdErr)
+			reply.Error = cmdErr.Error()
+		}
+		replies = append(replies, reply)
+		if aborted && len(reply.Error) <= 0 {
+			errs = append(errs, "transaction aborted")
+		} else {
+			errs = append(errs, reply.Error)
+		}
+	}
+	Audit().recordCommands(server, db, commands, errs)
+	for _, cmds := range commands {
+		c.appendHistory(server, strings.Join(cmds, " "))
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Replies  []pipelineReply `json:"replies"`
+		Aborted  bool            `json:"aborted,omitempty"`
+		Duration float64         `json:"duration"` // round-trip of whole pipeline in milliseconds
+	}{
+		Replies:  replies,
+		Aborted:  aborted,
+		Duration: toMillis(duration),
+	}
+	return
+}
+
+// GetHistory get executed command lines of server, latest at last
+func (c *cliService) GetHistory(server string) (resp types.JSResp) {
+	c.mutex.Lock()
+	history := slices.Clone(c.history[server])
+	c.mutex.Unlock()
+
+	resp.Success = true
+	resp.Data = struct {
+		History []string `json:"history"`
+	}{
+		History: history,
+	}
+	return
+}
+
+// GetCommandStats get latency of commands executed by cli on server, slowest p95 first,
+// all durations are in milliseconds
+func (c *cliService) GetCommandStats(server string) (resp types.JSResp) {
+	type commandStat struct {
+		Command string  `json:"command"`
+		Count   int64   `json:"count"`
+		Min     float64 `json:"min"`
+		Max     float64 `json:"max"`
+		Avg     float64 `json:"avg"`
+		P95     float64 `json:"p95"` // of recent executions
+	}
+
+	c.mutex.Lock()
+	stats := make([]commandStat, 0, len(c.latency[server]))
+	for cmd, l := range c.latency[server] {
+		stats = append(stats, commandStat{
+			Command: cmd,
+			Count:   l.count,
+			Min:     toMillis(l.min),
+			Max:     toMillis(l.max),
+			Avg:     toMillis(l.total / time.Duration(l.count)),
+			P95:     toMillis(l.p95()),
+		})
+	}
+	c.mutex.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].P95 == stats[j].P95 {
+			return stats[i].Command < stats[j].Command
+		}
+		return stats[i].P95 > stats[j].P95
+	})
+
+	resp.Success = true
+	resp.Data = struct {
+		Stats []commandStat `json:"stats"`
+	}{
+		Stats: stats,
+	}
+	return
+}
+
+// ResetCommandStats clear latency of commands executed by cli on server
+func (c *cliService) ResetCommandStats(server string) (resp types.JSResp) {
+	c.mutex.Lock()
+	delete(c.latency, server)
+	c.mutex.Unlock()
+
+	resp.Success = true
+	return
+}
+
 // CloseCli close cli session
 func (c *cliService) CloseCli(server string) (resp types.JSResp) {
 	c.mutex.Lock()
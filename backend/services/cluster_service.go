@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tinyrdm/backend/consts"
+	"tinyrdm/backend/types"
+	redis2 "tinyrdm/backend/utils/redis"
+	strutil "tinyrdm/backend/utils/string"
+)
+
+type slotRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type clusterNode struct {
+	ID        string        `json:"id"`
+	Addr      string        `json:"addr"` // ip:port for client connection
+	Hostname  string        `json:"hostname,omitempty"`
+	Role      string        `json:"role"` // master/replica
+	MasterID  string        `json:"masterId,omitempty"`
+	Flags     []string      `json:"flags"`
+	LinkState string        `json:"linkState"`
+	Slots     []slotRange   `json:"slots,omitempty"`
+	SlotCount int           `json:"slotCount"`
+	Failed    bool          `json:"failed"` // marked as fail by cluster or unreachable
+	Error     string        `json:"error,omitempty"`
+	Replicas  []clusterNode `json:"replicas,omitempty"`
+}
+
+type slotStat struct {
+	Slot  int    `json:"slot"`
+	Node  string `json:"node"`
+	Count int64  `json:"count"`
+	Keys  []any  `json:"keys"` // part of sampled keys in slot
+}
+
+type clusterService struct {
+	ctx context.Context
+}
+
+var cluster *clusterService
+var onceCluster sync.Once
+
+func Cluster() *clusterService {
+	if cluster == nil {
+		onceCluster.Do(func() {
+			cluster = &clusterService{}
+		})
+	}
+	return cluster
+}
+
+func (c *clusterService) Start(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// get cluster client of server
+func (c *clusterService) getClusterClient(server string) (*redis.ClusterClient, context.Context, error) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterClient, ok := item.client.(*redis.ClusterClient)
+	if !ok {
+		return nil, nil, errors.New("not a cluster connection")
+	}
+	return clusterClient, item.ctx, nil
+}
+
+// parse one line of CLUSTER NODES reply, like below
+// <id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> <slot> ... <slot>
+func (c *clusterService) parseNodeLine(line string) (node clusterNode, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 8 {
+		return
+	}
+	node.ID = fields[0]
+	addr, hostname, _ := strings.Cut(fields[1], ",")
+	node.Addr, _, _ = strings.Cut(addr, "@")
+	node.Hostname = hostname
+	node.Flags = strings.Split(fields[2], ",")
+	if slices.Contains(node.Flags, "master") {
+		node.Role = "master"
+	} else {
+		node.Role = "replica"
+	}
+	if fields[3] != "-" {
+		node.MasterID = fields[3]
+	}
+	node.LinkState = fields[7]
+	node.Failed = slices.Contains(node.Flags, "fail") || slices.Contains(node.Flags, "fail?")
+
+	for _, slot := range fields[8:] {
+		// skip importing or migrating slot like [slot->-id]
+		if strings.HasPrefix(slot, "[") {
+			continue
+		}
+		startStr, endStr, isRange := strings.Cut(slot, "-")
+		start, err := strconv.Atoi(startStr)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(endStr); err != nil {
+				continue
+			}
+		}
+		node.Slots = append(node.Slots, slotRange{Start: start, End: end})
+		node.SlotCount += end - start + 1
+	}
+	ok = true
+	return
+}
+
+// GetNodes get topology of cluster by CLUSTER NODES, replicas are grouped under their master,
+// unreachable nodes are marked as failed
+func (c *clusterService) GetNodes(server string) (resp types.JSResp) {
+	client, ctx, err := c.getClusterClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	res, err := client.ClusterNodes(ctx).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	// check reachability of each node
+	var mutex sync.Mutex
+	unreachable := map[string]string{}
+	_ = client.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
+		pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+		if pingErr := cli.Ping(pingCtx).Err(); pingErr != nil {
+			mutex.Lock()
+			unreachable[cli.Options().Addr] = pingErr.Error()
+			mutex.Unlock()
+		}
+		return nil
+	})
+
+	var nodes []clusterNode
+	for _, line := range strings.Split(res, "\n") {
+		if node, ok := c.parseNodeLine(line); ok {
+			if errMsg, ok := unreachable[node.Addr]; ok {
+				node.Failed = true
+				node.Error = errMsg
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	masters := make([]clusterNode, 0, len(nodes))
+	replicas := map[string][]clusterNode{}
+	for _, node := range nodes {
+		if node.Role == "master" {
+			masters = append(masters, node)
+		} else {
+			replicas[node.MasterID] = append(replicas[node.MasterID], node)
+		}
+	}
+	for i := range masters {
+		masters[i].Replicas = replicas[masters[i].ID]
+		delete(replicas, masters[i].ID)
+	}
+	// sort masters by first slot
+	sort.Slice(masters, func(i, j int) bool {
+		if len(masters[i].Slots) <= 0 || len(masters[j].Slots) <= 0 {
+			return len(masters[i].Slots) > len(masters[j].Slots)
+		}
+		return masters[i].Slots[0].Start < masters[j].Slots[0].Start
+	})
+	// replicas whose master is unknown
+	var orphans []clusterNode
+	for _, nodes := range replicas {
+		orphans = append(orphans, nodes...)
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Masters []clusterNode `json:"masters"`
+		Orphans []clusterNode `json:"orphans,omitempty"`
+	}{
+		Masters: masters,
+		Orphans: orphans,
+	}
+	return
+}
+
+// GetNodeInfo get INFO of specified node in cluster
+// @param nodeAddr address of node like ip:port
+func (c *clusterService) GetNodeInfo(server, nodeAddr string) (resp types.JSResp) {
+	client, ctx, err := c.getClusterClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	var info string
+	var found bool
+	var mutex sync.Mutex
+	err = client.ForEachShard(ctx, func(ctx context.Context, cli *redis.Client) error {
+		if cli.Options().Addr != nodeAddr {
+			return nil
+		}
+		res, infoErr := cli.Info(ctx).Result()
+		mutex.Lock()
+		found, info = true, res
+		mutex.Unlock()
+		return infoErr
+	})
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if !found {
+		resp.Msg = "no node found by address: " + nodeAddr
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Addr string                       `json:"addr"`
+		Info map[string]map[string]string `json:"info"`
+	}{
+		Addr: nodeAddr,
+		Info: Browser().parseInfo(info),
+	}
+	return
+}
+
+// SlotDistribution sample keys from each master concurrently by SCAN, and count sampled keys by slot and node
+// @param sampleSize total keys to sample, shared equally by masters
+func (c *clusterService) SlotDistribution(server string, sampleSize int) (resp types.JSResp) {
+	client, ctx, err := c.getClusterClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if sampleSize <= 0 {
+		sampleSize = consts.DEFAULT_SLOT_SAMPLE_SIZE
+	}
+
+	var masterCount int64
+	var mutex sync.Mutex
+	_ = client.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+		mutex.Lock()
+		masterCount += 1
+		mutex.Unlock()
+		return nil
+	})
+	if masterCount <= 0 {
+		resp.Msg = "no master node found"
+		return
+	}
+	perMaster := (int64(sampleSize) + masterCount - 1) / masterCount
+
+	const maxSlotKeys = 10
+	var sampled int64
+	slots := map[int]*slotStat{}
+	nodes := map[string]int64{}
+	scanSize := int64(Preferences().GetScanSize())
+	err = client.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+		addr := cli.Options().Addr
+		var cursor uint64
+		var count int64
+		for count < perMaster {
+			keys, nextCursor, scanErr := cli.Scan(ctx, cursor, "*", scanSize).Result()
+			if scanErr != nil {
+				return scanErr
+			}
+			cursor = nextCursor
+			if remain := perMaster - count; int64(len(keys)) > remain {
+				keys = keys[:remain]
+			}
+			count += int64(len(keys))
+
+			mutex.Lock()
+			for _, k := range keys {
+				slot := redis2.HashSlot(k)
+				stat, ok := slots[slot]
+				if !ok {
+					stat = &slotStat{Slot: slot, Node: addr}
+					slots[slot] = stat
+				}
+				stat.Count += 1
+				if len(stat.Keys) < maxSlotKeys {
+					stat.Keys = append(stat.Keys, strutil.EncodeRedisKey(k))
+				}
+			}
+			nodes[addr] += int64(len(keys))
+			sampled += int64(len(keys))
+			mutex.Unlock()
+
+			if cursor == 0 {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	const maxHotSlots = 20
+	hotSlots := make([]slotStat, 0, len(slots))
+	for _, stat := range slots {
+		hotSlots = append(hotSlots, *stat)
+	}
+	sort.Slice(hotSlots, func(i, j int) bool {
+		if hotSlots[i].Count == hotSlots[j].Count {
+			return hotSlots[i].Slot < hotSlots[j].Slot
+		}
+		return hotSlots[i].Count > hotSlots[j].Count
+	})
+	if len(hotSlots) > maxHotSlots {
+		hotSlots = hotSlots[:maxHotSlots]
+	}
+	slotCounts := make(map[int]int64, len(slots))
+	for slot, stat := range slots {
+		slotCounts[slot] = stat.Count
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Sampled  int64            `json:"sampled"`
+		Slots    map[int]int64    `json:"slots"` // count of sampled keys of each non-empty slot
+		Nodes    map[string]int64 `json:"nodes"` // count of sampled keys of each master
+		HotSlots []slotStat       `json:"hotSlots"`
+	}{
+		Sampled:  sampled,
+		Slots:    slotCounts,
+		Nodes:    nodes,
+		HotSlots: hotSlots,
+	}
+	return
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 	"tinyrdm/backend/types"
@@ -16,6 +17,8 @@ import (
 
 type monitorItem struct {
 	client    *redis.Client
+	ctx       context.Context
+	ctxCancel context.CancelFunc // abort the dedicated monitor connection
 	cmd       *redis.MonitorCmd
 	mutex     sync.Mutex
 	ch        chan string
@@ -23,6 +26,15 @@ type monitorItem struct {
 	eventName string
 }
 
+type monitorEntry struct {
+	Timestamp int64    `json:"timestamp"` // milliseconds
+	DB        int      `json:"db"`
+	Client    string   `json:"client"` // client address, or "lua" for script
+	Command   string   `json:"command"`
+	Args      []string `json:"args"`
+	Raw       string   `json:"raw"`
+}
+
 type monitorService struct {
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -50,18 +62,19 @@ func (c *monitorService) getItem(server string) (*monitorItem, error) {
 
 	item, ok := c.items[server]
 	if !ok {
-		var err error
 		conf := Connection().getConnection(server)
 		if conf == nil {
 			return nil, fmt.Errorf("no connection profile named: %s", server)
 		}
-		var uniClient redis.UniversalClient
-		if uniClient, err = Connection().createRedisClient(conf.ConnectionConfig); err != nil {
+		// use a dedicated client, the connection in MONITOR mode can not be shared with others
+		uniClient, err := Connection().createRedisClient(c.ctx, conf.ConnectionConfig, "monitor")
+		if err != nil {
 			return nil, err
 		}
 		var client *redis.Client
 		if client, ok = uniClient.(*redis.Client); !ok {
-			return nil, errors.New("create redis client fail")
+			uniClient.Close()
+			return nil, errors.New("MONITOR not supported in cluster mode")
 		}
 		item = &monitorItem{
 			client: client,
@@ -86,48 +99,51 @@ func (c *monitorService) StartMonitor(server string) (resp types.JSResp) {
 	item.ch = make(chan string)
 	item.closeCh = make(chan struct{})
 	item.eventName = "monitor:" + strconv.Itoa(int(time.Now().Unix()))
-	item.cmd = item.client.Monitor(c.ctx, item.ch)
+	item.ctx, item.ctxCancel = context.WithCancel(c.ctx)
+	item.cmd = item.client.Monitor(item.ctx, item.ch)
 	item.cmd.Start()
 
 	go c.processMonitor(&item.mutex, item.ch, item.closeCh, item.cmd, item.eventName)
 	resp.Success = true
 	resp.Data = struct {
 		EventName string `json:"eventName"`
+		Warning   string `json:"warning"`
 	}{
 		EventName: item.eventName,
+		Warning:   "MONITOR streams every command executed by the server and may degrade its performance, avoid running it on production servers for a long time",
 	}
 	return
 }
 
 func (c *monitorService) processMonitor(mutex *sync.Mutex, ch <-chan string, closeCh <-chan struct{}, cmd *redis.MonitorCmd, eventName string) {
-	cache := make([]string, 0, 1000)
+	cache := make([]monitorEntry, 0, 1000)
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
+	flush := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(cache) > 0 {
+			runtime.EventsEmit(c.ctx, eventName, cache)
+			cache = make([]monitorEntry, 0, 1000)
+		}
+	}
+
 	for {
 		select {
 		case data := <-ch:
 			if data != "OK" {
-				go func() {
-					mutex.Lock()
-					defer mutex.Unlock()
-					cache = append(cache, data)
-					if len(cache) > 300 {
-						runtime.EventsEmit(c.ctx, eventName, cache)
-						cache = cache[:0:cap(cache)]
-					}
-				}()
+				mutex.Lock()
+				cache = append(cache, c.parseMonitorLine(data))
+				full := len(cache) > 300
+				mutex.Unlock()
+				if full {
+					flush()
+				}
 			}
 
 		case <-ticker.C:
-			func() {
-				mutex.Lock()
-				defer mutex.Unlock()
-				if len(cache) > 0 {
-					runtime.EventsEmit(c.ctx, eventName, cache)
-					cache = cache[:0:cap(cache)]
-				}
-			}()
+			flush()
 
 		case <-closeCh:
 			// monitor stopped
@@ -137,6 +153,62 @@ func (c *monitorService) processMonitor(mutex *sync.Mutex, ch <-chan string, clo
 	}
 }
 
+// parse one line of monitor output, like below
+// 1339518083.107412 [0 127.0.0.1:60866] "keys" "*"
+func (c *monitorService) parseMonitorLine(line string) (entry monitorEntry) {
+	entry.Raw = line
+	timestamp, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return
+	}
+	if ts, err := strconv.ParseFloat(timestamp, 64); err == nil {
+		entry.Timestamp = int64(ts * 1000)
+	}
+
+	// client info in brackets: [db addr]
+	if !strings.HasPrefix(rest, "[") {
+		return
+	}
+	clientInfo, rest, ok := strings.Cut(rest[1:], "] ")
+	if !ok {
+		return
+	}
+	db, addr, _ := strings.Cut(clientInfo, " ")
+	entry.DB, _ = strconv.Atoi(db)
+	entry.Client = addr
+
+	// quoted arguments
+	var args []string
+	for len(rest) > 0 {
+		rest = strings.TrimLeft(rest, " ")
+		if !strings.HasPrefix(rest, "\"") {
+			break
+		}
+		// find the end quote which not escaped
+		end := 1
+		for end < len(rest) && rest[end] != '"' {
+			if rest[end] == '\\' {
+				end += 1
+			}
+			end += 1
+		}
+		if end >= len(rest) {
+			break
+		}
+		if arg, err := strconv.Unquote(rest[:end+1]); err == nil {
+			args = append(args, arg)
+		} else {
+			args = append(args, rest[1:end])
+		}
+		rest = rest[end+1:]
+	}
+	if len(args) > 0 {
+		entry.Command = args[0]
+		entry.Args = args[1:]
+	}
+	return
+}
+
 // StopMonitor stop monitor by server name
 func (c *monitorService) StopMonitor(server string) (resp types.JSResp) {
 	c.mutex.Lock()
@@ -149,6 +221,7 @@ func (c *monitorService) StopMonitor(server string) (resp types.JSResp) {
 	}
 
 	//close(item.ch)
+	item.ctxCancel()
 	item.client.Close()
 	close(item.closeCh)
 	delete(c.items, server)
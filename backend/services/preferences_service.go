@@ -205,6 +205,11 @@ func (p *preferencesService) GetScanSize() int {
 	return size
 }
 
+func (p *preferencesService) GetScanAutoTune() bool {
+	data := p.pref.GetPreferences()
+	return data.General.ScanAutoTune
+}
+
 func (p *preferencesService) GetDecoder() []convutil.CmdConvert {
 	data := p.pref.GetPreferences()
 	return sliceutil.FilterMap(data.Decoder, func(i int) (convutil.CmdConvert, bool) {
@@ -1,35 +1,176 @@
 package services
 
 import (
+	"container/list"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"hash/fnv"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"tinyrdm/backend/consts"
 	"tinyrdm/backend/types"
+	strutil "tinyrdm/backend/utils/string"
+	"unicode/utf8"
 )
 
 type pubsubItem struct {
+	server    string
 	client    redis.UniversalClient
 	pubsub    *redis.PubSub
 	mutex     sync.Mutex
 	closeCh   chan struct{}
+	stopOnce  *sync.Once // guard closing of "closeCh", renew for each subscription
 	eventName string
+	channels  []string
+	shard     bool         // subscribed by SSUBSCRIBE
+	cache     []subMessage // messages waiting to be emitted
+	received  int64        // total received messages
+	dropped   int64        // messages dropped due to buffer limit
+	paused    bool         // hold messages without emitting
+	filter    func(payload string) bool
+	dedup     *dedupCache  // nil if deduplication disabled
+	jsonFmt   string       // format of JSON payload, empty if disabled
+	history   []subMessage // ring buffer of recent messages
+	histPos   int          // next write position of history ring if full
+
+	historySize   int
+	batchSize     int
+	bufferLimit   int
+	flushInterval time.Duration
+	idleTimeout   time.Duration
+	lastActive    time.Time // last time of receiving message or pinged by frontend
+	suppressed    int64     // duplicated messages suppressed by dedup window
+	startTime     time.Time // time of subscription started
+
+	maxMessageSize  int  // max bytes of emitted payload, 0 means unlimited
+	keepFullPayload bool // keep full payload of truncated message in history
+}
+
+// check if subscription started, pubsub is replaced with item mutex locked
+func (item *pubsubItem) subscribed() bool {
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	return item.pubsub != nil
 }
 
 type subMessage struct {
 	Timestamp int64  `json:"timestamp"`
 	Channel   string `json:"channel"`
+	Pattern   string `json:"pattern,omitempty"` // matched pattern, empty if subscribed by SUBSCRIBE
+	Shard     bool   `json:"shard,omitempty"`   // message from shard channel
 	Message   string `json:"message"`
+	Encoding  string `json:"encoding"`            // "text" or "base64" if payload is not valid UTF-8
+	Operation string `json:"operation,omitempty"` // operation of keyspace notification
+	Key       string `json:"key,omitempty"`       // affected key of keyspace notification
+	Parsed    bool   `json:"parsed,omitempty"`    // payload is valid JSON object or array
+	Formatted string `json:"formatted,omitempty"` // formatted JSON of payload if parsed
+	Truncated bool   `json:"truncated,omitempty"` // payload exceeds max message size and truncated
+	Size      int    `json:"size,omitempty"`      // original bytes of payload if truncated
+	ID        int64  `json:"id,omitempty"`        // sequence of truncated message to get full payload by GetFullMessage
+
+	fullPayload string // full payload of truncated message, only kept in history
+}
+
+type subStatus struct {
+	Connected bool   `json:"connected"`
+	TimedOut  bool   `json:"timedOut,omitempty"` // stopped due to idle timeout
+	Error     string `json:"error,omitempty"`
+}
+
+// lru of recent message hashes, to suppress identical messages in time window
+type dedupCache struct {
+	window time.Duration
+	items  map[uint64]*list.Element
+	order  *list.List // elements of dedupEntry, the oldest at front
+}
+
+type dedupEntry struct {
+	hash uint64
+	last time.Time
+}
+
+func newDedupCache(window time.Duration) *dedupCache {
+	return &dedupCache{
+		window: window,
+		items:  map[uint64]*list.Element{},
+		order:  list.New(),
+	}
+}
+
+// check if the same message of channel received in window, and record it if not
+func (d *dedupCache) duplicated(channel, payload string, now time.Time) bool {
+	h := fnv.New64a()
+	h.Write([]byte(channel))
+	h.Write([]byte{0})
+	h.Write([]byte(payload))
+	hash := h.Sum64()
+
+	if elem, ok := d.items[hash]; ok {
+		entry := elem.Value.(*dedupEntry)
+		if now.Sub(entry.last) <= d.window {
+			return true
+		}
+		entry.last = now
+		d.order.MoveToBack(elem)
+	} else {
+		d.items[hash] = d.order.PushBack(&dedupEntry{hash: hash, last: now})
+	}
+
+	// evict expired entries and the least recent ones if exceed capacity
+	for front := d.order.Front(); front != nil; front = d.order.Front() {
+		entry := front.Value.(*dedupEntry)
+		if d.order.Len() <= subDedupCacheSize && now.Sub(entry.last) <= d.window {
+			break
+		}
+		d.order.Remove(front)
+		delete(d.items, entry.hash)
+	}
+	return false
 }
 
+const (
+	subReconnectMinBackoff = 1 * time.Second
+	subReconnectMaxBackoff = 30 * time.Second
+	subPausedBufferLimit   = 10000 // max held messages while paused if buffer is unbounded
+	pubRetryDelay          = 200 * time.Millisecond
+	subDedupCacheSize      = 1000                   // max message hashes kept for deduplication
+	replayMinRoundInterval = 100 * time.Millisecond // min delay between rounds of loop replay
+)
+
 type pubsubService struct {
-	ctx       context.Context
-	ctxCancel context.CancelFunc
-	mutex     sync.Mutex
-	items     map[string]*pubsubItem
+	ctx           context.Context
+	ctxCancel     context.CancelFunc
+	mutex         sync.Mutex
+	items         map[string]*pubsubItem
+	batchSize     int           // max messages of one emitting batch
+	bufferLimit   int           // high-water mark of cached messages, 0 means unbounded
+	flushInterval time.Duration // interval of flushing cached messages
+	historySize   int           // max messages kept in history of each subscription, 0 means disabled
+	idleTimeout   time.Duration // stop subscription if idle for a long time, 0 means disabled
+	publishRate   int           // max published messages per second of each server, 0 means unlimited
+
+	maxMessageSize  int  // max bytes of emitted payload, 0 means unlimited
+	keepFullPayload bool // keep full payload of truncated message in history for retrieving on demand
+
+	rateMutex   sync.Mutex
+	rateBuckets map[string]*rateBucket // publish rate limiter of each server
+}
+
+// token bucket of publishing rate limit
+type rateBucket struct {
+	tokens float64
+	last   time.Time
 }
 
 var pubsub *pubsubService
@@ -39,7 +180,11 @@ func Pubsub() *pubsubService {
 	if pubsub == nil {
 		oncePubsub.Do(func() {
 			pubsub = &pubsubService{
-				items: map[string]*pubsubItem{},
+				items:         map[string]*pubsubItem{},
+				rateBuckets:   map[string]*rateBucket{},
+				batchSize:     consts.DEFAULT_SUB_BATCH_SIZE,
+				flushInterval: consts.DEFAULT_SUB_FLUSH_INTERVAL * time.Millisecond,
+				historySize:   consts.DEFAULT_SUB_HISTORY_SIZE,
 			}
 		})
 	}
@@ -48,24 +193,30 @@ func Pubsub() *pubsubService {
 
 func (p *pubsubService) getItem(server string) (*pubsubItem, error) {
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
-
 	item, ok := p.items[server]
-	if !ok {
-		var err error
-		conf := Connection().getConnection(server)
-		if conf == nil {
-			return nil, fmt.Errorf("no connection profile named: %s", server)
-		}
-		var uniClient redis.UniversalClient
-		if uniClient, err = Connection().createRedisClient(conf.ConnectionConfig); err != nil {
-			return nil, err
-		}
-		item = &pubsubItem{
-			client: uniClient,
-		}
-		p.items[server] = item
+	p.mutex.Unlock()
+	if ok {
+		return item, nil
+	}
+
+	// acquire without lock so that an unreachable server will not block subscriptions of others
+	uniClient, err := Connection().acquireClient(p.ctx, server, -1)
+	if err != nil {
+		return nil, err
 	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if item, ok = p.items[server]; ok {
+		// created by another call concurrently, use that one instead
+		Connection().releaseClient(server, uniClient)
+		return item, nil
+	}
+	item = &pubsubItem{
+		server: server,
+		client: uniClient,
+	}
+	p.items[server] = item
 	return item, nil
 }
 
@@ -73,121 +224,1512 @@ func (p *pubsubService) Start(ctx context.Context) {
 	p.ctx, p.ctxCancel = context.WithCancel(ctx)
 }
 
+// SetBufferOptions set max batch size and flush interval of emitting messages,
+// only affect newly started subscriptions
+func (p *pubsubService) SetBufferOptions(maxBatch int, flushInterval time.Duration) (resp types.JSResp) {
+	if maxBatch <= 0 {
+		resp.Msg = "max batch size must be positive"
+		return
+	}
+	if flushInterval <= 0 {
+		resp.Msg = "flush interval must be positive"
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.batchSize = maxBatch
+	p.flushInterval = flushInterval
+	resp.Success = true
+	return
+}
+
 // Publish publish message to channel
-func (p *pubsubService) Publish(server, channel, payload string) (resp types.JSResp) {
-	rdb, err := Browser().getRedisClient(server, -1)
-	if err != nil {
-		resp.Msg = err.Error()
+// @param encoding encoding of payload, "base64" payload will be decoded before sending
+// @param retry max retry times if no subscriber received, subscribers may be re-registering after reconnect
+func (p *pubsubService) Publish(server, channel, payload, encoding string, retry int) (resp types.JSResp) {
+	var message any = payload
+	if encoding == types.MESSAGE_ENCODING_BASE64 {
+		raw, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			resp.Msg = "invalid base64 payload: " + err.Error()
+			return
+		}
+		message = raw
+	}
+
+	if !p.allowPublish(server, 1) {
+		resp.Msg = "rate limited"
 		return
 	}
 
-	var received int64
-	received, err = rdb.client.Publish(p.ctx, channel, payload).Result()
+	client, err := Connection().acquireClient(p.ctx, server, -1)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer Connection().releaseClient(server, client)
+
+	var received int64
+	var attempts int
+	for {
+		attempts += 1
+		received, err = client.Publish(p.ctx, channel, message).Result()
+		if err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		if received > 0 || attempts > retry {
+			break
+		}
+		select {
+		case <-p.ctx.Done():
+			resp.Msg = p.ctx.Err().Error()
+			return
+		case <-time.After(pubRetryDelay):
+		}
+	}
 
 	resp.Success = true
 	resp.Data = struct {
 		Received int64 `json:"received"`
+		Attempts int   `json:"attempts"`
 	}{
 		Received: received,
+		Attempts: attempts,
 	}
 	return
 }
 
-// StartSubscribe start to subscribe a channel
-func (p *pubsubService) StartSubscribe(server, channel string) (resp types.JSResp) {
-	item, err := p.getItem(server)
+// parse comma-delimited channel list, subscribe "*" if empty
+func (p *pubsubService) parseChannels(channel string) []string {
+	var channels []string
+	for _, ch := range strings.Split(channel, ",") {
+		if ch = strings.TrimSpace(ch); len(ch) > 0 && !slices.Contains(channels, ch) {
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) <= 0 {
+		channels = []string{"*"}
+	}
+	return channels
+}
+
+// check if channel contains glob metacharacters, which should be subscribed by PSUBSCRIBE
+func isPatternChannel(channel string) bool {
+	return strings.ContainsAny(channel, "*?[")
+}
+
+// SetBufferLimit set the high-water mark of cached messages, the oldest messages
+// will be dropped if exceeded. 0 means unbounded
+func (p *pubsubService) SetBufferLimit(highWater int) (resp types.JSResp) {
+	if highWater < 0 {
+		resp.Msg = "buffer limit must not be negative"
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.bufferLimit = highWater
+	resp.Success = true
+	return
+}
+
+// SetPublishRateLimit set max published messages per second of each server, 0 means unlimited
+func (p *pubsubService) SetPublishRateLimit(perSecond int) (resp types.JSResp) {
+	if perSecond < 0 {
+		resp.Msg = "publish rate limit must not be negative"
+		return
+	}
+
+	p.rateMutex.Lock()
+	defer p.rateMutex.Unlock()
+	p.publishRate = perSecond
+	p.rateBuckets = map[string]*rateBucket{}
+	resp.Success = true
+	return
+}
+
+// take n tokens from rate limiter of server, returns false if exceed the limit
+func (p *pubsubService) allowPublish(server string, n int) bool {
+	p.rateMutex.Lock()
+	defer p.rateMutex.Unlock()
+	if p.publishRate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	limit := float64(p.publishRate)
+	bucket, ok := p.rateBuckets[server]
+	if !ok {
+		bucket = &rateBucket{tokens: limit, last: now}
+		p.rateBuckets[server] = bucket
+	} else {
+		// refill tokens by elapsed time, allow burst up to one second
+		bucket.tokens = min(limit, bucket.tokens+now.Sub(bucket.last).Seconds()*limit)
+		bucket.last = now
+	}
+	if bucket.tokens < float64(n) {
+		return false
+	}
+	bucket.tokens -= float64(n)
+	return true
+}
+
+// SetHistorySize set max messages kept in history of each subscription, 0 means disabled.
+// only affect newly started subscriptions
+func (p *pubsubService) SetHistorySize(size int) (resp types.JSResp) {
+	if size < 0 {
+		resp.Msg = "history size must not be negative"
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.historySize = size
+	resp.Success = true
+	return
+}
+
+// SetMaxMessageSize set max bytes of emitted payload, oversized payload will be truncated. 0 means unlimited,
+// only affect newly started subscriptions
+// @param keepFull keep full payload of truncated message in history, which can be got by GetFullMessage
+func (p *pubsubService) SetMaxMessageSize(maxBytes int, keepFull bool) (resp types.JSResp) {
+	if maxBytes < 0 {
+		resp.Msg = "max message size must not be negative"
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.maxMessageSize, p.keepFullPayload = maxBytes, keepFull
+	resp.Success = true
+	return
+}
+
+// SetIdleTimeout set timeout of idle subscription in minutes, 0 means disabled.
+// subscription without any message received or pinged by KeepAlive within timeout will be stopped,
+// only affect newly started subscriptions
+func (p *pubsubService) SetIdleTimeout(minutes int) (resp types.JSResp) {
+	if minutes < 0 {
+		resp.Msg = "idle timeout must not be negative"
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.idleTimeout = time.Duration(minutes) * time.Minute
+	resp.Success = true
+	return
+}
+
+// KeepAlive refresh activity of subscription to prevent it from idle timeout
+func (p *pubsubService) KeepAlive(server string) (resp types.JSResp) {
+	p.mutex.Lock()
+	item, ok := p.items[server]
+	p.mutex.Unlock()
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + server
+		return
+	}
+
+	item.mutex.Lock()
+	item.lastActive = time.Now()
+	item.mutex.Unlock()
+	resp.Success = true
+	return
+}
+
+// PublishMulti publish the same message to multiple channels in one pipeline
+func (p *pubsubService) PublishMulti(server string, channels []string, payload string) (resp types.JSResp) {
+	if len(channels) <= 0 {
+		resp.Msg = "no channel to publish"
+		return
+	}
+	if !p.allowPublish(server, len(channels)) {
+		resp.Msg = "rate limited"
+		return
+	}
+
+	client, err := Connection().acquireClient(p.ctx, server, -1)
 	if err != nil {
 		resp.Msg = err.Error()
 		return
 	}
+	defer Connection().releaseClient(server, client)
 
-	item.closeCh = make(chan struct{})
-	item.eventName = "sub:" + strconv.Itoa(int(time.Now().Unix()))
-	if channel == "" {
-		channel = "*"
+	pipe := client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(channels))
+	for i, channel := range channels {
+		cmds[i] = pipe.Publish(p.ctx, channel, payload)
 	}
-	item.pubsub = item.client.PSubscribe(p.ctx, channel)
+	// error of each command will be reported separately
+	_, _ = pipe.Exec(p.ctx)
 
-	go p.processSubscribe(&item.mutex, item.pubsub.Channel(), item.closeCh, item.eventName)
-	resp.Success = true
+	type publishResult struct {
+		Channel  string `json:"channel"`
+		Received int64  `json:"received"`
+		Error    string `json:"error,omitempty"`
+	}
+	var failed int
+	results := make([]publishResult, len(channels))
+	for i, cmd := range cmds {
+		results[i].Channel = channels[i]
+		if cmdErr := cmd.Err(); cmdErr != nil {
+			results[i].Error = cmdErr.Error()
+			failed += 1
+		} else {
+			results[i].Received = cmd.Val()
+		}
+	}
+
+	resp.Success = failed < len(channels)
+	if failed > 0 {
+		resp.Msg = fmt.Sprintf("publish to %d of %d channels failed", failed, len(channels))
+	}
 	resp.Data = struct {
-		EventName string `json:"eventName"`
+		Results []publishResult `json:"results"`
 	}{
-		EventName: item.eventName,
+		Results: results,
 	}
 	return
 }
 
-func (p *pubsubService) processSubscribe(mutex *sync.Mutex, ch <-chan *redis.Message, closeCh <-chan struct{}, eventName string) {
-	cache := make([]subMessage, 0, 1000)
-	ticker := time.NewTicker(300 * time.Millisecond)
-	defer ticker.Stop()
+// ListChannels list active channels matched pattern by PUBSUB CHANNELS and PUBSUB SHARDCHANNELS with count
+// of subscribers, and count of subscribed patterns by PUBSUB NUMPAT, all masters are queried in cluster mode
+func (p *pubsubService) ListChannels(server, pattern string) (resp types.JSResp) {
+	if len(pattern) <= 0 {
+		pattern = "*"
+	}
 
-	for {
-		select {
-		case data := <-ch:
-			go func() {
-				timestamp := time.Now().UnixMilli()
-				mutex.Lock()
-				defer mutex.Unlock()
-				cache = append(cache, subMessage{
-					Timestamp: timestamp,
-					Channel:   data.Channel,
-					Message:   data.Payload,
-				})
-				if len(cache) > 300 {
-					runtime.EventsEmit(p.ctx, eventName, cache)
-					cache = cache[:0:cap(cache)]
-				}
-			}()
+	client, err := Connection().acquireClient(p.ctx, server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	defer Connection().releaseClient(server, client)
 
-		case <-ticker.C:
-			func() {
-				mutex.Lock()
-				defer mutex.Unlock()
-				if len(cache) > 0 {
-					runtime.EventsEmit(p.ctx, eventName, cache)
-					cache = cache[:0:cap(cache)]
+	type channelItem struct {
+		Channel     string `json:"channel"`
+		Subscribers int64  `json:"subscribers"`
+		Sharded     bool   `json:"sharded,omitempty"`
+	}
+	var mutex sync.Mutex
+	var numPat int64
+	channels := map[string]*channelItem{}
+	merge := func(counts map[string]int64, sharded bool) {
+		for channel, count := range counts {
+			id := "c:" + channel
+			if sharded {
+				id = "s:" + channel
+			}
+			if ch, ok := channels[id]; ok {
+				ch.Subscribers += count
+			} else {
+				channels[id] = &channelItem{
+					Channel:     channel,
+					Subscribers: count,
+					Sharded:     sharded,
 				}
-			}()
+			}
+		}
+	}
+	query := func(ctx context.Context, cli redis.Cmdable) error {
+		names, err := cli.PubSubChannels(ctx, pattern).Result()
+		if err != nil {
+			return err
+		}
+		counts := map[string]int64{}
+		if len(names) > 0 {
+			if counts, err = cli.PubSubNumSub(ctx, names...).Result(); err != nil {
+				return err
+			}
+		}
+		pat, err := cli.PubSubNumPat(ctx).Result()
+		if err != nil {
+			return err
+		}
+		// sharded channels are supported since Redis 7.0, ignore error of old versions
+		shardCounts := map[string]int64{}
+		if shardNames, shardErr := cli.PubSubShardChannels(ctx, pattern).Result(); shardErr == nil && len(shardNames) > 0 {
+			if shardCounts, shardErr = cli.PubSubShardNumSub(ctx, shardNames...).Result(); shardErr != nil {
+				shardCounts = map[string]int64{}
+			}
+		}
 
-		case <-closeCh:
-			// subscribe stopped
-			return
+		mutex.Lock()
+		defer mutex.Unlock()
+		numPat += pat
+		merge(counts, false)
+		merge(shardCounts, true)
+		return nil
+	}
+
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// subscribers are connected to different nodes in cluster mode
+		err = cluster.ForEachMaster(p.ctx, func(ctx context.Context, cli *redis.Client) error {
+			return query(ctx, cli)
+		})
+	} else {
+		err = query(p.ctx, client)
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	list := make([]channelItem, 0, len(channels))
+	for _, ch := range channels {
+		list = append(list, *ch)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Channel == list[j].Channel {
+			return !list[i].Sharded
 		}
+		return list[i].Channel < list[j].Channel
+	})
+
+	resp.Success = true
+	resp.Data = struct {
+		Channels []channelItem `json:"channels"`
+		NumPat   int64         `json:"numPat"`
+	}{
+		Channels: list,
+		NumPat:   numPat,
 	}
+	return
 }
 
-// StopSubscribe stop subscribe by server name
-func (p *pubsubService) StopSubscribe(server string) (resp types.JSResp) {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+// StartSubscribe start to subscribe channels
+// @param channel comma-delimited channels or patterns, subscribe all("*") if empty
+// @param option filter of message payload. if option.ChannelsKey specified, channels are read from the key and
+// re-read periodically, changes of subscribed channels will be emitted by event eventName+":channels"
+func (p *pubsubService) StartSubscribe(server, channel string, option types.SubscribeOption) (resp types.JSResp) {
+	channels := p.parseChannels(channel)
+	var keyClient redis.UniversalClient
+	if len(option.ChannelsKey) > 0 {
+		var err error
+		if keyClient, err = Connection().acquireClient(p.ctx, server, option.ChannelsKeyDB); err != nil {
+			resp.Msg = err.Error()
+			return
+		}
+		if channels, err = p.readChannelsKey(keyClient, option.ChannelsKey); err != nil || len(channels) <= 0 {
+			Connection().releaseClient(server, keyClient)
+			if err != nil {
+				resp.Msg = err.Error()
+			} else {
+				resp.Msg = "no channel found in key: " + option.ChannelsKey
+			}
+			return
+		}
+	}
 
-	item, ok := p.items[server]
-	if !ok || item.pubsub == nil {
-		resp.Success = true
+	var onStarted func(item *pubsubItem, closeCh <-chan struct{})
+	if keyClient != nil {
+		interval := time.Duration(option.ChannelsRefresh) * time.Millisecond
+		if interval <= 0 {
+			interval = consts.DEFAULT_SUB_CHANNELS_REFRESH * time.Millisecond
+		}
+		onStarted = func(item *pubsubItem, closeCh <-chan struct{}) {
+			go p.watchChannelsKey(item, keyClient, option.ChannelsKey, interval, closeCh)
+		}
+	}
+	eventName, err := p.startSubscribe(server, channels, false, option, onStarted)
+	if err != nil {
+		if keyClient != nil {
+			Connection().releaseClient(server, keyClient)
+		}
+		resp.Msg = err.Error()
 		return
 	}
 
-	//item.pubsub.Unsubscribe(p.ctx, "*")
-	item.pubsub.Close()
-	close(item.closeCh)
-	delete(p.items, server)
 	resp.Success = true
+	resp.Data = struct {
+		EventName string `json:"eventName"`
+	}{
+		EventName: eventName,
+	}
 	return
 }
 
-// StopAll stop all subscribe
-func (p *pubsubService) StopAll() {
-	if p.ctxCancel != nil {
-		p.ctxCancel()
+// read channel list from set or list key, empty if key not exists
+func (p *pubsubService) readChannelsKey(client redis.UniversalClient, key string) ([]string, error) {
+	keyType, err := client.Type(p.ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	var members []string
+	switch keyType {
+	case "none":
+	case "set":
+		members, err = client.SMembers(p.ctx, key).Result()
+	case "list":
+		members, err = client.LRange(p.ctx, key, 0, -1).Result()
+	default:
+		err = fmt.Errorf("channels key should be set or list, but got %s", keyType)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	for server := range p.items {
+	channels := make([]string, 0, len(members))
+	for _, ch := range members {
+		if ch = strings.TrimSpace(ch); len(ch) > 0 && !slices.Contains(channels, ch) {
+			channels = append(channels, ch)
+		}
+	}
+	sort.Strings(channels)
+	return channels, nil
+}
+
+// re-read channels key periodically and sync subscribed channels until subscription stopped
+func (p *pubsubService) watchChannelsKey(item *pubsubItem, client redis.UniversalClient, key string, interval time.Duration, closeCh <-chan struct{}) {
+	defer Connection().releaseClient(item.server, client)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var emptyWarned bool
+	for {
+		select {
+		case <-closeCh:
+			return
+		case <-ticker.C:
+		}
+
+		channels, err := p.readChannelsKey(client, key)
+		if err != nil {
+			// keep current subscription, try again next time
+			continue
+		}
+		var added, removed []string
+		if len(channels) <= 0 {
+			// keep current channels rather than leaving a subscription without any channel, warn only once
+			if emptyWarned {
+				continue
+			}
+			emptyWarned = true
+			err = errors.New("no channel found in key: " + key)
+		} else {
+			emptyWarned = false
+			added, removed, err = p.syncChannels(item, channels, closeCh)
+		}
+		if len(added) > 0 || len(removed) > 0 || err != nil {
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			// report channels actually subscribed, which may differ from key content if failed
+			item.mutex.Lock()
+			select {
+			case <-closeCh:
+				// stopped while syncing, the item may be reused by another subscription
+				item.mutex.Unlock()
+				return
+			default:
+			}
+			subscribed, eventName := slices.Clone(item.channels), item.eventName
+			item.mutex.Unlock()
+			runtime.EventsEmit(p.ctx, eventName+":channels", map[string]any{
+				"channels": subscribed,
+				"added":    added,
+				"removed":  removed,
+				"error":    errMsg,
+			})
+		}
+	}
+}
+
+// subscribe new channels and unsubscribe removed ones to match the channel list
+// @return added and removed channels, only the succeeded ones are included if failed halfway
+func (p *pubsubService) syncChannels(item *pubsubItem, channels []string, closeCh <-chan struct{}) (added, removed []string, err error) {
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	select {
+	case <-closeCh:
+		return
+	default:
+	}
+
+	for _, ch := range channels {
+		if !slices.Contains(item.channels, ch) {
+			added = append(added, ch)
+		}
+	}
+	for _, ch := range item.channels {
+		if !slices.Contains(channels, ch) {
+			removed = append(removed, ch)
+		}
+	}
+
+	split := func(chs []string) (plain, patterns []string) {
+		for _, ch := range chs {
+			if isPatternChannel(ch) {
+				patterns = append(patterns, ch)
+			} else {
+				plain = append(plain, ch)
+			}
+		}
+		return
+	}
+	addPlain, addPatterns := split(added)
+	removePlain, removePatterns := split(removed)
+	steps := []struct {
+		channels []string
+		add      bool
+		exec     func(context.Context, ...string) error
+	}{
+		{addPlain, true, item.pubsub.Subscribe},
+		{addPatterns, true, item.pubsub.PSubscribe},
+		{removePlain, false, item.pubsub.Unsubscribe},
+		{removePatterns, false, item.pubsub.PUnsubscribe},
+	}
+	// keep channels of succeeded steps only, so that failed ones will be retried next time
+	added, removed = nil, nil
+	for _, step := range steps {
+		if len(step.channels) <= 0 {
+			continue
+		}
+		if err = step.exec(p.ctx, step.channels...); err != nil {
+			break
+		}
+		if step.add {
+			added = append(added, step.channels...)
+		} else {
+			removed = append(removed, step.channels...)
+		}
+	}
+
+	synced := make([]string, 0, len(item.channels)+len(added))
+	for _, ch := range item.channels {
+		if !slices.Contains(removed, ch) {
+			synced = append(synced, ch)
+		}
+	}
+	item.channels = append(synced, added...)
+	return
+}
+
+// StartShardSubscribe start to subscribe shard channels by SSUBSCRIBE
+// @param channel comma-delimited shard channels, which should be hashed to the same slot in cluster mode
+func (p *pubsubService) StartShardSubscribe(server, channel string) (resp types.JSResp) {
+	var channels []string
+	for _, ch := range p.parseChannels(channel) {
+		if isPatternChannel(ch) {
+			resp.Msg = "pattern is not supported by shard channel: " + ch
+			return
+		}
+		channels = append(channels, ch)
+	}
+	eventName, err := p.startSubscribe(server, channels, true, types.SubscribeOption{}, nil)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		EventName string `json:"eventName"`
+	}{
+		EventName: eventName,
+	}
+	return
+}
+
+// StartKeyspaceSubscribe start to subscribe keyspace notifications of database
+// @param eventType "keyspace" or "keyevent"
+// @param pattern key pattern for "keyspace", or event pattern for "keyevent"
+// @param enableNotify set "notify-keyspace-events" if not configured on server
+func (p *pubsubService) StartKeyspaceSubscribe(server string, db int, eventType, pattern string, enableNotify bool) (resp types.JSResp) {
+	if eventType != "keyevent" {
+		eventType = "keyspace"
+	}
+	if len(pattern) <= 0 {
+		pattern = "*"
+	}
+
+	item, err := p.getItem(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	// check keyspace notification config
+	var warning string
+	notifyConf, err := item.client.ConfigGet(p.ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		warning = "can not get \"notify-keyspace-events\" config: " + err.Error()
+	} else if len(notifyConf["notify-keyspace-events"]) <= 0 {
+		if enableNotify {
+			setNotify := func(ctx context.Context, cli redis.UniversalClient) error {
+				return cli.ConfigSet(ctx, "notify-keyspace-events", "KEA").Err()
+			}
+			if cluster, ok := item.client.(*redis.ClusterClient); ok {
+				err = cluster.ForEachShard(p.ctx, func(ctx context.Context, cli *redis.Client) error {
+					return setNotify(ctx, cli)
+				})
+			} else {
+				err = setNotify(p.ctx, item.client)
+			}
+			if err != nil {
+				resp.Msg = "set \"notify-keyspace-events\" fail: " + err.Error()
+				return
+			}
+		} else {
+			warning = "keyspace notification is disabled, \"notify-keyspace-events\" is not configured"
+		}
+	}
+
+	channel := fmt.Sprintf("__%s@%d__:%s", eventType, db, pattern)
+	eventName, err := p.startSubscribe(server, []string{channel}, false, types.SubscribeOption{}, nil)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		EventName string `json:"eventName"`
+		Warning   string `json:"warning,omitempty"`
+	}{
+		EventName: eventName,
+		Warning:   warning,
+	}
+	return
+}
+
+// parse operation and affected key from keyspace notification
+// "__keyspace@0__:mykey" with payload "set", or "__keyevent@0__:set" with payload "mykey"
+func parseKeyspaceEvent(channel, payload string) (operation, key string, ok bool) {
+	if !strings.HasPrefix(channel, "__key") {
+		return
+	}
+	prefix, suffix, found := strings.Cut(channel, "__:")
+	if !found {
+		return
+	}
+	switch {
+	case strings.HasPrefix(prefix, "__keyspace@"):
+		return payload, suffix, true
+	case strings.HasPrefix(prefix, "__keyevent@"):
+		return suffix, payload, true
+	}
+	return
+}
+
+// build message filter by substring or regular expression, nil if no filter
+func (p *pubsubService) buildFilter(option types.SubscribeOption) (func(string) bool, error) {
+	if len(option.Filter) <= 0 {
+		return nil, nil
+	}
+	if option.Regex {
+		reg, err := regexp.Compile(option.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter regex: %s", err.Error())
+		}
+		return reg.MatchString, nil
+	}
+	return func(payload string) bool {
+		return strings.Contains(payload, option.Filter)
+	}, nil
+}
+
+// subscribe channels and start processing messages, the previous subscription of server will be stopped
+// @param onStarted called with the new subscription before any other call could stop or replace it, nil if not required
+func (p *pubsubService) startSubscribe(server string, channels []string, shard bool, option types.SubscribeOption,
+	onStarted func(item *pubsubItem, closeCh <-chan struct{})) (string, error) {
+	filter, err := p.buildFilter(option)
+	if err != nil {
+		return "", err
+	}
+
+	// stop the previous subscription of server, or its goroutine and connection will be leaked
+	p.mutex.Lock()
+	if prev, ok := p.items[server]; ok && prev.subscribed() {
+		p.stopSubscribe(server, prev)
+	}
+	p.mutex.Unlock()
+
+	item, err := p.getItem(server)
+	if err != nil {
+		return "", err
+	}
+
+	pubsub, err := p.subscribeChannels(item.client, channels, shard)
+	if err != nil {
+		return "", err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.items[server] != item {
+		// stopped while subscribing, the item is removed and its client is released
+		pubsub.Close()
+		return "", errors.New("subscription of server is stopped")
+	}
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	if item.pubsub != nil {
+		// started by another call concurrently
+		pubsub.Close()
+		return "", errors.New("subscription of server is already started")
+	}
+	item.pubsub = pubsub
+	item.channels = channels
+	item.shard = shard
+	item.filter = filter
+	item.dedup = nil
+	if option.DedupWindow > 0 {
+		item.dedup = newDedupCache(time.Duration(option.DedupWindow) * time.Millisecond)
+	}
+	item.jsonFmt = option.JSONFormat
+	item.closeCh = make(chan struct{})
+	item.stopOnce = &sync.Once{}
+	item.eventName = "sub:" + strconv.Itoa(int(time.Now().Unix()))
+	item.startTime = time.Now()
+	item.batchSize, item.bufferLimit, item.flushInterval = p.batchSize, p.bufferLimit, p.flushInterval
+	item.historySize = p.historySize
+	item.idleTimeout = p.idleTimeout
+	item.maxMessageSize, item.keepFullPayload = p.maxMessageSize, p.keepFullPayload
+
+	go p.processSubscribe(item, item.pubsub.Channel(), item.closeCh)
+	if onStarted != nil {
+		onStarted(item, item.closeCh)
+	}
+	return item.eventName, nil
+}
+
+// subscribe channels by SUBSCRIBE and patterns by PSUBSCRIBE in one pubsub connection,
+// or subscribe all channels by SSUBSCRIBE if shard
+func (p *pubsubService) subscribeChannels(client redis.UniversalClient, channels []string, shard bool) (*redis.PubSub, error) {
+	if shard {
+		pubsub := client.SSubscribe(p.ctx)
+		if err := pubsub.SSubscribe(p.ctx, channels...); err != nil {
+			pubsub.Close()
+			return nil, err
+		}
+		return pubsub, nil
+	}
+
+	var plainChannels, patterns []string
+	for _, ch := range channels {
+		if isPatternChannel(ch) {
+			patterns = append(patterns, ch)
+		} else {
+			plainChannels = append(plainChannels, ch)
+		}
+	}
+
+	var err error
+	pubsub := client.Subscribe(p.ctx)
+	if len(plainChannels) > 0 {
+		err = pubsub.Subscribe(p.ctx, plainChannels...)
+	}
+	if err == nil && len(patterns) > 0 {
+		err = pubsub.PSubscribe(p.ctx, patterns...)
+	}
+	if err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	return pubsub, nil
+}
+
+func (p *pubsubService) emitStatus(eventName string, connected bool, err error) {
+	status := subStatus{
+		Connected: connected,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	runtime.EventsEmit(p.ctx, eventName+":status", status)
+}
+
+// resubscribe all channels with backoff until succeed or subscription stopped,
+// the shared client will redial by itself
+// @return message channel of new subscription, nil if subscription stopped
+func (p *pubsubService) reconnect(item *pubsubItem, closeCh <-chan struct{}) <-chan *redis.Message {
+	backoff := subReconnectMinBackoff
+	for {
+		select {
+		case <-closeCh:
+			return nil
+		case <-time.After(backoff):
+		}
+
+		err := func() error {
+			item.mutex.Lock()
+			defer item.mutex.Unlock()
+			select {
+			case <-closeCh:
+				// stopped while waiting
+				return nil
+			default:
+			}
+			pubsub, err := p.subscribeChannels(item.client, item.channels, item.shard)
+			if err != nil {
+				return err
+			}
+			item.pubsub.Close()
+			item.pubsub = pubsub
+			return nil
+		}()
+		if err == nil {
+			select {
+			case <-closeCh:
+				return nil
+			default:
+			}
+			p.emitStatus(item.eventName, true, nil)
+			item.mutex.Lock()
+			defer item.mutex.Unlock()
+			return item.pubsub.Channel()
+		}
+
+		p.emitStatus(item.eventName, false, err)
+		backoff = min(backoff*2, subReconnectMaxBackoff)
+	}
+}
+
+// rebind subscription of server after connection recovered,
+// the broken pubsub connection will be closed to trigger resubscribing
+func (p *pubsubService) rebind(server string) {
+	p.mutex.Lock()
+	item, ok := p.items[server]
+	p.mutex.Unlock()
+	if !ok {
+		return
+	}
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	if item.pubsub == nil {
+		return
+	}
+	if err := item.pubsub.Ping(p.ctx); err != nil {
+		item.pubsub.Close()
+	}
+}
+
+// emit all cached messages in batches, should be called with item mutex locked
+func (p *pubsubService) flushCache(item *pubsubItem) {
+	for start := 0; start < len(item.cache); start += item.batchSize {
+		end := min(start+item.batchSize, len(item.cache))
+		runtime.EventsEmit(p.ctx, item.eventName, item.cache[start:end])
+	}
+	item.cache = item.cache[:0:cap(item.cache)]
+}
+
+func (p *pubsubService) processSubscribe(item *pubsubItem, ch <-chan *redis.Message, closeCh <-chan struct{}) {
+	item.mutex.Lock()
+	item.cache = make([]subMessage, 0, item.batchSize)
+	item.received, item.dropped = 0, 0
+	item.paused = false
+	item.history, item.histPos = make([]subMessage, 0, item.historySize), 0
+	item.lastActive = time.Now()
+	flushInterval := item.flushInterval
+	item.mutex.Unlock()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data, ok := <-ch:
+			if !ok {
+				select {
+				case <-closeCh:
+					// subscribe stopped
+					return
+				default:
+				}
+
+				// connection dropped, try to reconnect
+				p.emitStatus(item.eventName, false, errors.New("subscription connection closed"))
+				if ch = p.reconnect(item, closeCh); ch == nil {
+					return
+				}
+				continue
+			}
+			// append synchronously to keep messages in order
+			p.handleMessage(item, data)
+
+		case <-ticker.C:
+			idle := func() bool {
+				item.mutex.Lock()
+				defer item.mutex.Unlock()
+				if !item.paused && len(item.cache) > 0 {
+					p.flushCache(item)
+				}
+				return item.idleTimeout > 0 && time.Since(item.lastActive) > item.idleTimeout
+			}()
+			if idle {
+				p.mutex.Lock()
+				p.stopSubscribe(item.server, item)
+				p.mutex.Unlock()
+				runtime.EventsEmit(p.ctx, item.eventName+":status", subStatus{
+					Connected: false,
+					TimedOut:  true,
+				})
+				return
+			}
+
+		case <-closeCh:
+			// subscribe stopped
+			return
+		}
+	}
+}
+
+// append received message to cache, and emit if cache is full
+func (p *pubsubService) handleMessage(item *pubsubItem, data *redis.Message) {
+	timestamp := time.Now().UnixMilli()
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	item.received += 1
+	item.lastActive = time.Now()
+	if item.filter != nil && !item.filter(data.Payload) {
+		return
+	}
+	if item.dedup != nil && item.dedup.duplicated(data.Channel, data.Payload, time.UnixMilli(timestamp)) {
+		item.suppressed += 1
+		return
+	}
+	payload := data.Payload
+	msg := subMessage{
+		Timestamp: timestamp,
+		Channel:   data.Channel,
+		Pattern:   data.Pattern,
+		Shard:     item.shard,
+		Encoding:  types.MESSAGE_ENCODING_TEXT,
+	}
+	if item.maxMessageSize > 0 && len(payload) > item.maxMessageSize {
+		// cut at boundary of utf-8 character
+		end := item.maxMessageSize
+		for end > 0 && !utf8.RuneStart(payload[end]) {
+			end -= 1
+		}
+		payload = payload[:end]
+		msg.Truncated, msg.Size = true, len(data.Payload)
+		if item.keepFullPayload {
+			msg.ID = item.received
+		}
+	}
+	if op, key, ok := parseKeyspaceEvent(data.Channel, data.Payload); ok {
+		msg.Operation, msg.Key = op, key
+	}
+	msg.Message, msg.Encoding = p.encodePayload(payload)
+	if msg.Encoding == types.MESSAGE_ENCODING_TEXT && !msg.Truncated && len(item.jsonFmt) > 0 {
+		msg.Parsed, msg.Formatted = p.formatJSON(payload, item.jsonFmt)
+	}
+	item.cache = append(item.cache, msg)
+	if msg.ID > 0 {
+		msg.fullPayload = data.Payload
+	}
+	p.appendHistory(item, msg)
+	bufferLimit := item.bufferLimit
+	if item.paused && bufferLimit <= 0 {
+		bufferLimit = subPausedBufferLimit
+	}
+	if bufferLimit > 0 && len(item.cache) > bufferLimit {
+		// exceed high-water mark, drop the oldest messages
+		drop := len(item.cache) - bufferLimit
+		item.cache = slices.Delete(item.cache, 0, drop)
+		item.dropped += int64(drop)
+	}
+	if !item.paused && len(item.cache) >= item.batchSize {
+		p.flushCache(item)
+	}
+}
+
+// encode payload by base64 if it's not valid utf-8
+func (p *pubsubService) encodePayload(payload string) (string, string) {
+	if !utf8.ValidString(payload) {
+		return base64.StdEncoding.EncodeToString([]byte(payload)), types.MESSAGE_ENCODING_BASE64
+	}
+	return payload, types.MESSAGE_ENCODING_TEXT
+}
+
+// format payload if it's a valid JSON object or array
+func (p *pubsubService) formatJSON(payload, format string) (bool, string) {
+	trimmed := strings.TrimSpace(payload)
+	if len(trimmed) <= 0 || (trimmed[0] != '{' && trimmed[0] != '[') || !json.Valid([]byte(trimmed)) {
+		return false, ""
+	}
+	if format == types.JSON_FORMAT_MINIFY {
+		return true, strutil.JSONMinify(trimmed)
+	}
+	return true, strutil.JSONBeautify(trimmed, "  ")
+}
+
+// save message to history ring buffer, should be called with item mutex locked
+func (p *pubsubService) appendHistory(item *pubsubItem, msg subMessage) {
+	if item.historySize <= 0 {
+		return
+	}
+	if len(item.history) < item.historySize {
+		item.history = append(item.history, msg)
+	} else {
+		item.history[item.histPos] = msg
+		item.histPos = (item.histPos + 1) % item.historySize
+	}
+}
+
+// GetHistory get recent messages of subscription in chronological order
+// @param limit max count of messages, return all history if limit <= 0
+func (p *pubsubService) GetHistory(server string, limit int) (resp types.JSResp) {
+	p.mutex.Lock()
+	item, ok := p.items[server]
+	p.mutex.Unlock()
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + server
+		return
+	}
+
+	list := p.historySnapshot(item)
+	if total := len(list); limit > 0 && limit < total {
+		list = list[total-limit:]
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		List []subMessage `json:"list"`
+	}{
+		List: list,
+	}
+	return
+}
+
+// GetFullMessage get full payload of truncated message kept in history
+// @param id sequence of truncated message
+func (p *pubsubService) GetFullMessage(server string, id int64) (resp types.JSResp) {
+	p.mutex.Lock()
+	item, ok := p.items[server]
+	p.mutex.Unlock()
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + server
+		return
+	}
+
+	item.mutex.Lock()
+	idx := slices.IndexFunc(item.history, func(msg subMessage) bool {
+		return msg.ID == id
+	})
+	var payload string
+	if idx >= 0 {
+		payload = item.history[idx].fullPayload
+	}
+	item.mutex.Unlock()
+	if idx < 0 || len(payload) <= 0 {
+		resp.Msg = "full payload of message not found or expired from history"
+		return
+	}
+
+	message, encoding := p.encodePayload(payload)
+	resp.Success = true
+	resp.Data = struct {
+		Message  string `json:"message"`
+		Encoding string `json:"encoding"`
+	}{
+		Message:  message,
+		Encoding: encoding,
+	}
+	return
+}
+
+// copy history of subscription in chronological order
+func (p *pubsubService) historySnapshot(item *pubsubItem) []subMessage {
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	// reorder ring buffer from the oldest one
+	list := make([]subMessage, 0, len(item.history))
+	list = append(list, item.history[item.histPos:]...)
+	list = append(list, item.history[:item.histPos]...)
+	return list
+}
+
+// Replay republish recorded messages in history of subscription, and keep the relative interval between messages.
+// progress will be emitted to event "replay:"+serialNo, send "replay:stop:"+serialNo to cancel
+// @param source server name of the subscription which history recorded
+// @param targetChannel channel to publish, publish to original channel of each message if empty
+// @param speedFactor scale of replay speed, 2 means twice as fast as recorded
+// @param loop replay from beginning repeatedly until canceled, rounds are separated by the average message interval
+func (p *pubsubService) Replay(server, source, targetChannel string, speedFactor float64, loop bool, serialNo string) (resp types.JSResp) {
+	p.mutex.Lock()
+	item, ok := p.items[source]
+	p.mutex.Unlock()
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + source
+		return
+	}
+	messages := p.historySnapshot(item)
+	if len(messages) <= 0 {
+		resp.Msg = "no recorded message to replay"
+		return
+	}
+	if speedFactor <= 0 {
+		speedFactor = 1
+	}
+
+	client, err := Connection().acquireClient(p.ctx, server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	ctx, cancelFunc := context.WithCancel(p.ctx)
+	cancelStopEvent := runtime.EventsOnce(ctx, "replay:stop:"+serialNo, func(data ...any) {
+		cancelFunc()
+	})
+
+	go func() {
+		defer Connection().releaseClient(server, client)
+		defer cancelFunc()
+		defer cancelStopEvent()
+
+		type replayProgress struct {
+			Published int64  `json:"published"`
+			Total     int    `json:"total"` // messages of each round
+			Round     int    `json:"round"`
+			Done      bool   `json:"done"`
+			Canceled  bool   `json:"canceled,omitempty"`
+			Error     string `json:"error,omitempty"`
+		}
+		processEvent := "replay:" + serialNo
+		progress := replayProgress{Total: len(messages)}
+		lastEmit := time.Now()
+		defer func() {
+			progress.Done = true
+			runtime.EventsEmit(p.ctx, processEvent, progress)
+		}()
+
+		// delay between rounds is the average interval of messages, avoid publishing in a tight loop
+		var roundInterval time.Duration
+		if len(messages) > 1 {
+			span := messages[len(messages)-1].Timestamp - messages[0].Timestamp
+			roundInterval = time.Duration(float64(span)/float64(len(messages)-1)/speedFactor) * time.Millisecond
+		}
+		roundInterval = max(roundInterval, replayMinRoundInterval)
+
+		for {
+			progress.Round += 1
+			for i, msg := range messages {
+				if i > 0 || progress.Round > 1 {
+					interval := roundInterval
+					if i > 0 {
+						interval = time.Duration(float64(msg.Timestamp-messages[i-1].Timestamp)/speedFactor) * time.Millisecond
+					}
+					select {
+					case <-ctx.Done():
+						progress.Canceled = true
+						return
+					case <-time.After(interval):
+					}
+				} else if ctx.Err() != nil {
+					progress.Canceled = true
+					return
+				}
+
+				var payload any = msg.Message
+				if len(msg.fullPayload) > 0 {
+					// republish the original one of truncated message
+					payload = msg.fullPayload
+				} else if msg.Encoding == types.MESSAGE_ENCODING_BASE64 {
+					if raw, decodeErr := base64.StdEncoding.DecodeString(msg.Message); decodeErr == nil {
+						payload = raw
+					}
+				}
+				channel := targetChannel
+				if len(channel) <= 0 {
+					channel = msg.Channel
+				}
+				if !p.allowPublish(server, 1) {
+					progress.Error = "rate limited"
+					return
+				}
+				var pubErr error
+				if msg.Shard && len(targetChannel) <= 0 {
+					pubErr = client.SPublish(ctx, channel, payload).Err()
+				} else {
+					pubErr = client.Publish(ctx, channel, payload).Err()
+				}
+				if pubErr != nil {
+					if errors.Is(pubErr, context.Canceled) {
+						progress.Canceled = true
+					} else {
+						progress.Error = pubErr.Error()
+					}
+					return
+				}
+				progress.Published += 1
+				if time.Since(lastEmit).Milliseconds() > 100 {
+					lastEmit = time.Now()
+					runtime.EventsEmit(p.ctx, processEvent, progress)
+				}
+			}
+			if !loop {
+				return
+			}
+		}
+	}()
+
+	resp.Success = true
+	resp.Data = struct {
+		EventName string `json:"eventName"`
+		Total     int    `json:"total"`
+	}{
+		EventName: "replay:" + serialNo,
+		Total:     len(messages),
+	}
+	return
+}
+
+// Pause stop emitting messages of subscription, messages will be held until resumed
+func (p *pubsubService) Pause(server string) (resp types.JSResp) {
+	return p.setPaused(server, true)
+}
+
+// Resume flush held messages and continue emitting
+func (p *pubsubService) Resume(server string) (resp types.JSResp) {
+	return p.setPaused(server, false)
+}
+
+func (p *pubsubService) setPaused(server string, paused bool) (resp types.JSResp) {
+	p.mutex.Lock()
+	item, ok := p.items[server]
+	p.mutex.Unlock()
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + server
+		return
+	}
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	item.paused = paused
+	buffered := len(item.cache)
+	if !paused {
+		p.flushCache(item)
+	}
+	resp.Success = true
+	resp.Data = struct {
+		Buffered int `json:"buffered"`
+	}{
+		Buffered: buffered,
+	}
+	return
+}
+
+// GetSubscribeStats get received/dropped message count and current buffer depth of subscription
+func (p *pubsubService) GetSubscribeStats(server string) (resp types.JSResp) {
+	p.mutex.Lock()
+	item, ok := p.items[server]
+	p.mutex.Unlock()
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + server
+		return
+	}
+
+	item.mutex.Lock()
+	defer item.mutex.Unlock()
+	resp.Success = true
+	resp.Data = struct {
+		Received   int64 `json:"received"`
+		Dropped    int64 `json:"dropped"`
+		Suppressed int64 `json:"suppressed"` // duplicated messages suppressed by dedup window
+		Buffered   int   `json:"buffered"`
+	}{
+		Received:   item.received,
+		Dropped:    item.dropped,
+		Suppressed: item.suppressed,
+		Buffered:   len(item.cache),
+	}
+	return
+}
+
+// ListSubscriptions list active subscriptions of all servers
+func (p *pubsubService) ListSubscriptions() (resp types.JSResp) {
+	type subscription struct {
+		Server    string   `json:"server"`
+		EventName string   `json:"eventName"`
+		Channels  []string `json:"channels"` // channels and patterns
+		Shard     bool     `json:"shard,omitempty"`
+		Received  int64    `json:"received"`
+		StartTime int64    `json:"startTime"` // milliseconds
+	}
+
+	p.mutex.Lock()
+	subs := make([]subscription, 0, len(p.items))
+	for server, item := range p.items {
+		item.mutex.Lock()
+		if item.pubsub != nil {
+			subs = append(subs, subscription{
+				Server:    server,
+				EventName: item.eventName,
+				Channels:  slices.Clone(item.channels),
+				Shard:     item.shard,
+				Received:  item.received,
+				StartTime: item.startTime.UnixMilli(),
+			})
+		}
+		item.mutex.Unlock()
+	}
+	p.mutex.Unlock()
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].Server < subs[j].Server
+	})
+
+	resp.Success = true
+	resp.Data = struct {
+		Subscriptions []subscription `json:"subscriptions"`
+	}{
+		Subscriptions: subs,
+	}
+	return
+}
+
+// Unsubscribe stop subscribe one channel or pattern, keep other subscriptions alive
+func (p *pubsubService) Unsubscribe(server, channel string) (resp types.JSResp) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	item, ok := p.items[server]
+	if !ok || !item.subscribed() {
+		resp.Msg = "no subscription of server: " + server
+		return
+	}
+
+	channel = strings.TrimSpace(channel)
+	item.mutex.Lock()
+	idx := slices.Index(item.channels, channel)
+	if idx < 0 {
+		item.mutex.Unlock()
+		resp.Msg = "channel not subscribed: " + channel
+		return
+	}
+
+	remaining := len(item.channels) - 1
+	if remaining <= 0 {
+		// the last channel removed, stop the whole subscription
+		item.mutex.Unlock()
+		p.stopSubscribe(server, item)
+	} else {
+		var err error
+		if item.shard {
+			err = item.pubsub.SUnsubscribe(p.ctx, channel)
+		} else if isPatternChannel(channel) {
+			err = item.pubsub.PUnsubscribe(p.ctx, channel)
+		} else {
+			err = item.pubsub.Unsubscribe(p.ctx, channel)
+		}
+		if err != nil {
+			item.mutex.Unlock()
+			resp.Msg = err.Error()
+			return
+		}
+		item.channels = slices.Delete(item.channels, idx, idx+1)
+		item.mutex.Unlock()
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Remaining int `json:"remaining"`
+	}{
+		Remaining: remaining,
+	}
+	return
+}
+
+// close pubsub and remove item, should be called with mutex locked
+// it's safe to be called multiple times, only the first call takes effect
+func (p *pubsubService) stopSubscribe(server string, item *pubsubItem) {
+	item.stopOnce.Do(func() {
+		// close "closeCh" first, so that closing of message channel will not be treated as connection dropped
+		close(item.closeCh)
+		item.mutex.Lock()
+		//item.pubsub.Unsubscribe(p.ctx, "*")
+		if item.shard {
+			_ = item.pubsub.SUnsubscribe(p.ctx)
+		}
+		item.pubsub.Close()
+		item.channels = nil
+		item.mutex.Unlock()
+	})
+	if p.items[server] == item {
+		delete(p.items, server)
+		Connection().releaseClient(server, item.client)
+	}
+}
+
+// StopSubscribe stop subscribe by server name
+func (p *pubsubService) StopSubscribe(server string) (resp types.JSResp) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	item, ok := p.items[server]
+	if !ok {
+		resp.Success = true
+		return
+	}
+	if !item.subscribed() {
+		// never subscribed successfully, just release the client
+		delete(p.items, server)
+		Connection().releaseClient(server, item.client)
+		resp.Success = true
+		return
+	}
+
+	p.stopSubscribe(server, item)
+	resp.Success = true
+	return
+}
+
+// Shutdown stop all subscriptions gracefully, the cached messages of each subscription will be emitted
+// after subscription closed, and waiting will be aborted if not finished in timeout
+func (p *pubsubService) Shutdown(timeout time.Duration) {
+	p.mutex.Lock()
+	items := make(map[string]*pubsubItem, len(p.items))
+	for server, item := range p.items {
+		items[server] = item
+	}
+	p.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for server, item := range items {
+			// stop receiving new messages first
+			p.StopSubscribe(server)
+			item.mutex.Lock()
+			if len(item.cache) > 0 {
+				p.flushCache(item)
+			}
+			item.mutex.Unlock()
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("shutdown subscriptions timeout after %s\n", timeout)
+	}
+	if p.ctxCancel != nil {
+		p.ctxCancel()
+	}
+}
+
+// StopAll stop all subscribe
+func (p *pubsubService) StopAll() {
+	if p.ctxCancel != nil {
+		p.ctxCancel()
+	}
+
+	// snapshot servers first, ranging items without lock may race with subscribing
+	p.mutex.Lock()
+	servers := make([]string, 0, len(p.items))
+	for server := range p.items {
+		servers = append(servers, server)
+	}
+	p.mutex.Unlock()
+
+	for _, server := range servers {
 		p.StopSubscribe(server)
 	}
 }
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"tinyrdm/backend/types"
+)
+
+// add a subscription item without connecting to server
+func newTestPubsubItem(p *pubsubService, server string) *pubsubItem {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	item := &pubsubItem{
+		server:   server,
+		client:   client,
+		pubsub:   client.Subscribe(p.ctx),
+		closeCh:  make(chan struct{}),
+		stopOnce: &sync.Once{},
+	}
+	p.mutex.Lock()
+	p.items[server] = item
+	p.mutex.Unlock()
+	return item
+}
+
+func TestStopSubscribeConcurrently(t *testing.T) {
+	p := Pubsub()
+	p.Start(context.Background())
+	item := newTestPubsubItem(p, "stop_concurrently")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp := p.StopSubscribe(item.server); !resp.Success {
+				t.Errorf("stop subscribe fail: %s", resp.Msg)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-item.closeCh:
+	default:
+		t.Fatal("subscription is not closed")
+	}
+	p.mutex.Lock()
+	_, ok := p.items[item.server]
+	p.mutex.Unlock()
+	if ok {
+		t.Fatal("subscription is not removed after stopped")
+	}
+}
+
+func TestProcessSubscribeCount(t *testing.T) {
+	const senders, perSender = 4, 2500
+	const total = senders * perSender
+
+	p := Pubsub()
+	p.Start(context.Background())
+	item := newTestPubsubItem(p, "process_count")
+	defer p.StopSubscribe(item.server)
+	// never emit during test, which requires frontend runtime
+	item.batchSize = total + 1
+	item.flushInterval = time.Hour
+
+	ch := make(chan *redis.Message)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.processSubscribe(item, ch, item.closeCh)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perSender; j++ {
+				ch <- &redis.Message{
+					Channel: "channel" + strconv.Itoa(i),
+					Payload: strconv.Itoa(j),
+				}
+			}
+		}(i)
+	}
+	// read stats while receiving
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			p.ListSubscriptions()
+		}
+	}()
+	wg.Wait()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		item.mutex.Lock()
+		received, cached := item.received, len(item.cache)
+		item.mutex.Unlock()
+		if received == total && cached == total {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expect %d messages received, got %d received and %d cached", total, received, cached)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.StopSubscribe(item.server)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("processSubscribe not exit after subscription stopped")
+	}
+}
+
+// start a minimal server which speaks RESP2 and only understands pub/sub commands
+func startFakeRedis(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveFakeRedis(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	subscribed := map[string]struct{}{}
+	for {
+		args, err := readFakeCommand(reader)
+		if err != nil {
+			return
+		}
+		var reply strings.Builder
+		switch cmd := strings.ToLower(args[0]); cmd {
+		case "hello":
+			reply.WriteString("-ERR unknown command 'HELLO'\r\n")
+		case "ping":
+			if len(subscribed) > 0 {
+				reply.WriteString("*2\r\n$4\r\npong\r\n$0\r\n\r\n")
+			} else {
+				reply.WriteString("+PONG\r\n")
+			}
+		case "subscribe", "psubscribe", "unsubscribe", "punsubscribe":
+			channels := args[1:]
+			if len(channels) <= 0 && strings.HasSuffix(cmd, "unsubscribe") {
+				for ch := range subscribed {
+					channels = append(channels, ch)
+				}
+				if len(channels) <= 0 {
+					fmt.Fprintf(&reply, "*3\r\n$%d\r\n%s\r\n$-1\r\n:0\r\n", len(cmd), cmd)
+				}
+			}
+			for _, ch := range channels {
+				if strings.HasSuffix(cmd, "unsubscribe") {
+					delete(subscribed, ch)
+				} else {
+					subscribed[ch] = struct{}{}
+				}
+				fmt.Fprintf(&reply, "*3\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n:%d\r\n", len(cmd), cmd, len(ch), ch, len(subscribed))
+			}
+		default:
+			reply.WriteString("+OK\r\n")
+		}
+		if _, err = io.WriteString(conn, reply.String()); err != nil {
+			return
+		}
+	}
+}
+
+// read one command sent as RESP array of bulk strings
+func readFakeCommand(reader *bufio.Reader) ([]string, error) {
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+	line, err := readLine()
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimPrefix(line, "*"))
+	if err != nil || count <= 0 {
+		return nil, fmt.Errorf("invalid command: %s", line)
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if line, err = readLine(); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(line, "$"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument: %s", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// put a shared client of server in advance, so that no connection profile is required
+func seedSharedClient(t *testing.T, server, addr string) *sharedClient {
+	client := redis.NewClient(&redis.Options{
+		Addr:            addr,
+		Protocol:        2,
+		DisableIdentity: true,
+	})
+	sc := &sharedClient{
+		client:  client,
+		refs:    1,
+		checkCh: make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+	}
+	c := Connection()
+	c.sharedMutex.Lock()
+	c.shared[server] = append(c.shared[server], sc)
+	c.sharedMutex.Unlock()
+	t.Cleanup(func() {
+		c.sharedMutex.Lock()
+		delete(c.shared, server)
+		c.sharedMutex.Unlock()
+		client.Close()
+	})
+	return sc
+}
+
+func TestStopAllWhileSubscribing(t *testing.T) {
+	addr := startFakeRedis(t)
+	p := Pubsub()
+	p.Start(context.Background())
+	servers := []string{"stop_all_1", "stop_all_2", "stop_all_3"}
+	shared := make([]*sharedClient, 0, len(servers))
+	for _, server := range servers {
+		shared = append(shared, seedSharedClient(t, server, addr))
+	}
+
+	var wg sync.WaitGroup
+	started := make(chan struct{})
+	var startOnce sync.Once
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			server := servers[i%len(servers)]
+			for j := 0; j < 50; j++ {
+				channel := fmt.Sprintf("ch%d_%d,pattern%d_*", i, j, i)
+				if resp := p.StartSubscribe(server, channel, types.SubscribeOption{}); resp.Success {
+					startOnce.Do(func() {
+						close(started)
+					})
+				}
+			}
+		}(i)
+	}
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no subscription started")
+	}
+	p.StopAll()
+	wg.Wait()
+	// subscriptions may be added after snapshot of StopAll
+	p.StopAll()
+
+	p.mutex.Lock()
+	remaining := len(p.items)
+	p.mutex.Unlock()
+	if remaining > 0 {
+		t.Fatalf("%d subscriptions remaining after stopped all", remaining)
+	}
+	c := Connection()
+	c.sharedMutex.Lock()
+	defer c.sharedMutex.Unlock()
+	for i, sc := range shared {
+		if sc.refs != 1 {
+			t.Errorf("shared client of %s leaked, refs: %d", servers[i], sc.refs)
+		}
+	}
+}
+
+func TestAccessorsWhileSubscribing(t *testing.T) {
+	addr := startFakeRedis(t)
+	p := Pubsub()
+	p.Start(context.Background())
+	const server = "accessors"
+	seedSharedClient(t, server, addr)
+	defer p.StopAll()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			p.StartSubscribe(server, fmt.Sprintf("ch%d", i), types.SubscribeOption{})
+		}
+	}()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				// no message received, so that none of them need frontend runtime
+				p.KeepAlive(server)
+				p.GetHistory(server, 0)
+				p.GetFullMessage(server, 1)
+				p.Replay(server, server, "", 1, false, "accessors")
+				p.Pause(server)
+				p.Resume(server)
+				p.GetSubscribeStats(server)
+				p.Unsubscribe(server, "unknown")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strings"
+	"sync"
+	storage2 "tinyrdm/backend/storage"
+	"tinyrdm/backend/types"
+	strutil "tinyrdm/backend/utils/string"
+)
+
+type scriptService struct {
+	ctx     context.Context
+	scripts *storage2.ScriptsStorage
+}
+
+var script *scriptService
+var onceScript sync.Once
+
+func Script() *scriptService {
+	if script == nil {
+		onceScript.Do(func() {
+			script = &scriptService{
+				scripts: storage2.NewScripts(),
+			}
+		})
+	}
+	return script
+}
+
+func (s *scriptService) Start(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// ListScripts list all saved Lua scripts
+func (s *scriptService) ListScripts() (resp types.JSResp) {
+	resp.Success = true
+	resp.Data = struct {
+		Scripts types.Scripts `json:"scripts"`
+	}{
+		Scripts: s.scripts.GetScripts(),
+	}
+	return
+}
+
+// SaveScript save Lua script to library, the script with the same name will be replaced
+func (s *scriptService) SaveScript(name, body string) (resp types.JSResp) {
+	name = strings.TrimSpace(name)
+	if len(name) <= 0 {
+		resp.Msg = "script name is required"
+		return
+	}
+	if len(strings.TrimSpace(body)) <= 0 {
+		resp.Msg = "script body is required"
+		return
+	}
+
+	if err := s.scripts.SaveScript(name, body); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Success = true
+	return
+}
+
+// DeleteScript remove Lua script from library
+func (s *scriptService) DeleteScript(name string) (resp types.JSResp) {
+	if err := s.scripts.DeleteScript(name); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Success = true
+	return
+}
+
+// RunScript run saved Lua script by SCRIPT LOAD and EVALSHA, fallback to EVAL if script not cached by server,
+// reply is formatted like redis-cli
+func (s *scriptService) RunScript(server string, db int, name string, keys []string, args []any) (resp types.JSResp) {
+	var scriptErr string
+	defer func() {
+		// script error is reported in data, keep it as failure in audit log
+		auditResp := resp
+		if len(scriptErr) > 0 {
+			auditResp.Success, auditResp.Msg = false, scriptErr
+		}
+		Audit().record(server, db, "script", strings.Join(keys, ","), "run "+name, &auditResp)
+	}()
+	sc := s.scripts.GetScript(name)
+	if sc == nil {
+		resp.Msg = "no script named: " + name
+		return
+	}
+
+	item, err := Browser().getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	var result any
+	sha, err := client.ScriptLoad(ctx, sc.Body).Result()
+	if err == nil {
+		result, err = client.EvalSha(ctx, sha, keys, args...).Result()
+	}
+	if err != nil && (strings.HasPrefix(err.Error(), "NOSCRIPT") || len(sha) <= 0) {
+		// script cache may be flushed, or SCRIPT command is not permitted
+		result, err = client.Eval(ctx, sc.Body, keys, args...).Result()
+	}
+
+	var output, errMsg string
+	if err == nil || errors.Is(err, redis.Nil) {
+		output = strutil.FormatReply(result)
+	} else {
+		output = strutil.FormatReply(err)
+		errMsg = err.Error()
+	}
+	scriptErr = errMsg
+
+	resp.Success = true
+	resp.Data = struct {
+		Output string `json:"output"`
+		SHA    string `json:"sha,omitempty"`
+		Error  string `json:"error,omitempty"`
+	}{
+		Output: output,
+		SHA:    sha,
+		Error:  errMsg,
+	}
+	return
+}
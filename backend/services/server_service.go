@@ -0,0 +1,1078 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tinyrdm/backend/consts"
+	"tinyrdm/backend/types"
+	redis2 "tinyrdm/backend/utils/redis"
+	strutil "tinyrdm/backend/utils/string"
+)
+
+type statsItem struct {
+	closeCh   chan struct{}
+	eventName string
+}
+
+type serverStats struct {
+	Timestamp        int64   `json:"timestamp"` // milliseconds
+	OpsPerSec        float64 `json:"opsPerSec"` // computed from delta of total_commands_processed
+	InstantOps       int64   `json:"instantOps"`
+	UsedMemory       int64   `json:"usedMemory"`
+	ConnectedClients int64   `json:"connectedClients"`
+	InputKbps        float64 `json:"inputKbps"`
+	OutputKbps       float64 `json:"outputKbps"`
+	HitRate          float64 `json:"hitRate"` // percent of keyspace hits during the interval
+	Error            string  `json:"error,omitempty"`
+}
+
+type configItem struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Mutable     bool   `json:"mutable"`     // can be modified by CONFIG SET
+	NeedRewrite bool   `json:"needRewrite"` // modified at runtime, requires CONFIG REWRITE to persist
+}
+
+type latencyEvent struct {
+	Event     string `json:"event"`
+	Timestamp int64  `json:"timestamp"` // seconds of latest spike
+	Latest    int64  `json:"latest"`    // milliseconds
+	Max       int64  `json:"max"`       // milliseconds
+}
+
+type latencySample struct {
+	Timestamp int64 `json:"timestamp"` // seconds
+	Latency   int64 `json:"latency"`   // milliseconds
+}
+
+type memoryGroup struct {
+	Prefix string `json:"prefix"` // empty if key has no delimiter
+	Type   string `json:"type"`
+	Count  int64  `json:"count"`
+	Memory int64  `json:"memory"` // bytes reported by MEMORY USAGE
+}
+
+// parameters can not be modified by CONFIG SET
+var immutableConfigs = map[string]struct{}{
+	"daemonize": {}, "databases": {}, "io-threads": {}, "unixsocket": {}, "unixsocketperm": {},
+	"logfile": {}, "syslog-enabled": {}, "syslog-ident": {}, "syslog-facility": {}, "supervised": {},
+	"pidfile": {}, "aclfile": {}, "cluster-enabled": {}, "cluster-config-file": {}, "always-show-logo": {},
+	"set-proc-title": {}, "disable-thp": {}, "enable-protected-configs": {}, "enable-debug-command": {},
+	"enable-module-command": {},
+}
+
+type serverService struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	mutex     sync.Mutex
+	items     map[string]*statsItem
+	modified  map[string]map[string]struct{} // parameters modified at runtime of each server
+}
+
+var server *serverService
+var onceServer sync.Once
+
+func Server() *serverService {
+	if server == nil {
+		onceServer.Do(func() {
+			server = &serverService{
+				items:    map[string]*statsItem{},
+				modified: map[string]map[string]struct{}{},
+			}
+		})
+	}
+	return server
+}
+
+func (s *serverService) Start(ctx context.Context) {
+	s.ctx, s.ctxCancel = context.WithCancel(ctx)
+}
+
+// convert info value to number if possible
+func (s *serverService) parseInfoValue(val string) any {
+	if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(val, 64); err == nil {
+		return f
+	}
+	return val
+}
+
+// parse info content into lowercase sections with typed value
+func (s *serverService) parseInfo(info string) map[string]map[string]any {
+	result := map[string]map[string]any{}
+	for section, items := range Browser().parseInfo(info) {
+		section = strings.ToLower(section)
+		sectionInfo := make(map[string]any, len(items))
+		for k, v := range items {
+			if section == "keyspace" {
+				// db0:keys=2,expires=1,avg_ttl=1877111749
+				dbInfo := map[string]any{}
+				for _, item := range strings.Split(v, ",") {
+					if dk, dv, ok := strings.Cut(item, "="); ok {
+						dbInfo[dk] = s.parseInfoValue(dv)
+					}
+				}
+				sectionInfo[k] = dbInfo
+			} else {
+				sectionInfo[k] = s.parseInfoValue(v)
+			}
+		}
+		result[section] = sectionInfo
+	}
+	return result
+}
+
+// GetInfo get server info, parsed into sections with typed values and some derived metrics
+func (s *serverService) GetInfo(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	res, err := client.Info(ctx).Result()
+	if err != nil {
+		resp.Msg = "get server info fail:" + err.Error()
+		return
+	}
+	info := s.parseInfo(res)
+
+	// derived metrics
+	var hitRate float64
+	if stats, ok := info["stats"]; ok {
+		hits, _ := stats["keyspace_hits"].(int64)
+		misses, _ := stats["keyspace_misses"].(int64)
+		if hits+misses > 0 {
+			hitRate = float64(hits) / float64(hits+misses) * 100
+		}
+	}
+	var totalKeys int64
+	for _, db := range info["keyspace"] {
+		if dbInfo, ok := db.(map[string]any); ok {
+			keys, _ := dbInfo["keys"].(int64)
+			totalKeys += keys
+		}
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Info      map[string]map[string]any `json:"info"`
+		HitRate   float64                   `json:"hitRate"` // percent of keyspace hits
+		TotalKeys int64                     `json:"totalKeys"`
+	}{
+		Info:      info,
+		HitRate:   hitRate,
+		TotalKeys: totalKeys,
+	}
+	return
+}
+
+// GetConfig get parameters matched pattern by CONFIG GET, sorted by name
+// @param pattern glob-style pattern, match all if empty
+func (s *serverService) GetConfig(server, pattern string) (resp types.JSResp) {
+	if len(pattern) <= 0 {
+		pattern = "*"
+	}
+
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	configs, err := client.ConfigGet(ctx, pattern).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	s.mutex.Lock()
+	modified := s.modified[server]
+	list := make([]configItem, 0, len(configs))
+	for name, value := range configs {
+		_, immutable := immutableConfigs[name]
+		_, changed := modified[name]
+		list = append(list, configItem{
+			Name:        name,
+			Value:       value,
+			Mutable:     !immutable,
+			NeedRewrite: changed,
+		})
+	}
+	s.mutex.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	resp.Success = true
+	resp.Data = struct {
+		Configs []configItem `json:"configs"`
+	}{
+		Configs: list,
+	}
+	return
+}
+
+// SetConfig modify parameter at runtime by CONFIG SET, apply to all master nodes in cluster mode
+// the modification will be lost after restart unless RewriteConfig is called
+func (s *serverService) SetConfig(server, param, value string) (resp types.JSResp) {
+	defer Audit().record(server, -1, "config_set", "", strings.Join(maskSecretArgs([]string{"config", "set", param, value})[2:], " "), &resp)
+	param = strings.ToLower(strings.TrimSpace(param))
+	if len(param) <= 0 {
+		resp.Msg = "parameter name is required"
+		return
+	}
+	if _, immutable := immutableConfigs[param]; immutable {
+		resp.Msg = fmt.Sprintf("parameter \"%s\" can not be modified at runtime", param)
+		return
+	}
+
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			if cerr := cli.ConfigSet(ctx, param, value).Err(); cerr != nil {
+				return fmt.Errorf("%s: %s", cli.Options().Addr, cerr.Error())
+			}
+			return nil
+		})
+	} else {
+		err = client.ConfigSet(ctx, param, value).Err()
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	s.mutex.Lock()
+	if _, ok := s.modified[server]; !ok {
+		s.modified[server] = map[string]struct{}{}
+	}
+	s.modified[server][param] = struct{}{}
+	s.mutex.Unlock()
+
+	resp.Success = true
+	resp.Data = struct {
+		NeedRewrite bool `json:"needRewrite"`
+	}{
+		NeedRewrite: true,
+	}
+	return
+}
+
+// RewriteConfig persist runtime parameters to config file by CONFIG REWRITE
+func (s *serverService) RewriteConfig(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			if cerr := cli.ConfigRewrite(ctx).Err(); cerr != nil {
+				return fmt.Errorf("%s: %s", cli.Options().Addr, cerr.Error())
+			}
+			return nil
+		})
+	} else {
+		err = client.ConfigRewrite(ctx).Err()
+	}
+	if err != nil {
+		// server started without config file
+		resp.Msg = "rewrite config fail: " + err.Error()
+		return
+	}
+
+	s.mutex.Lock()
+	delete(s.modified, server)
+	s.mutex.Unlock()
+
+	resp.Success = true
+	return
+}
+
+// check if latency monitor is enabled, returns warning if not
+func (s *serverService) checkLatencyMonitor(ctx context.Context, client redis.UniversalClient) string {
+	conf, err := client.ConfigGet(ctx, "latency-monitor-threshold").Result()
+	if err != nil {
+		return ""
+	}
+	if threshold, ok := conf["latency-monitor-threshold"]; ok && (threshold == "0" || len(threshold) <= 0) {
+		return "latency monitor is disabled, set \"latency-monitor-threshold\" to a positive value(milliseconds) to enable it"
+	}
+	return ""
+}
+
+// GetLatencyEvents get latest latency spikes of all events by LATENCY LATEST, and advices from LATENCY DOCTOR
+func (s *serverService) GetLatencyEvents(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	res, err := client.Do(ctx, "LATENCY", "LATEST").Slice()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	// each event like: [event, timestamp, latest, max]
+	events := make([]latencyEvent, 0, len(res))
+	for _, r := range res {
+		fields, ok := r.([]any)
+		if !ok || len(fields) < 4 {
+			continue
+		}
+		event := latencyEvent{
+			Event: strutil.AnyToString(fields[0], "", 0),
+		}
+		ts, _ := strutil.AnyToInt(fields[1])
+		latest, _ := strutil.AnyToInt(fields[2])
+		maxLatency, _ := strutil.AnyToInt(fields[3])
+		event.Timestamp, event.Latest, event.Max = int64(ts), int64(latest), int64(maxLatency)
+		events = append(events, event)
+	}
+	doctor, _ := client.Do(ctx, "LATENCY", "DOCTOR").Text()
+
+	resp.Success = true
+	resp.Data = struct {
+		Events  []latencyEvent `json:"events"`
+		Doctor  string         `json:"doctor"`
+		Warning string         `json:"warning,omitempty"`
+	}{
+		Events:  events,
+		Doctor:  doctor,
+		Warning: s.checkLatencyMonitor(ctx, client),
+	}
+	return
+}
+
+// GetLatencyHistory get latency samples of event by LATENCY HISTORY
+func (s *serverService) GetLatencyHistory(server, event string) (resp types.JSResp) {
+	if len(event) <= 0 {
+		resp.Msg = "event name is required"
+		return
+	}
+
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	res, err := client.Do(ctx, "LATENCY", "HISTORY", event).Slice()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	// each sample like: [timestamp, latency]
+	samples := make([]latencySample, 0, len(res))
+	for _, r := range res {
+		fields, ok := r.([]any)
+		if !ok || len(fields) < 2 {
+			continue
+		}
+		ts, _ := strutil.AnyToInt(fields[0])
+		latency, _ := strutil.AnyToInt(fields[1])
+		samples = append(samples, latencySample{
+			Timestamp: int64(ts),
+			Latency:   int64(latency),
+		})
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Event   string          `json:"event"`
+		Samples []latencySample `json:"samples"`
+		Warning string          `json:"warning,omitempty"`
+	}{
+		Event:   event,
+		Samples: samples,
+		Warning: s.checkLatencyMonitor(ctx, client),
+	}
+	return
+}
+
+// ResetLatency reset latency data of all events by LATENCY RESET
+func (s *serverService) ResetLatency(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	count, err := client.Do(ctx, "LATENCY", "RESET").Int64()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Count int64 `json:"count"` // count of reset events
+	}{
+		Count: count,
+	}
+	return
+}
+
+// MemoryReport sample keys of database by SCAN, group them by key prefix and type and report estimated memory
+// of each group, partial result will be emitted to event "memreport:"+serialNo, send "memreport:stop:"+serialNo to cancel
+// @param sampleSize max keys to sample, use default if not positive
+// @param delimiter separator of key prefix, use key separator of connection if empty
+func (s *serverService) MemoryReport(server string, db int, sampleSize int, delimiter string, serialNo string) (resp types.JSResp) {
+	if sampleSize <= 0 {
+		sampleSize = consts.DEFAULT_MEMORY_SAMPLE_SIZE
+	}
+	if len(delimiter) <= 0 {
+		if conf := Connection().getConnection(server); conf != nil {
+			delimiter = conf.KeySeparator
+		}
+		if len(delimiter) <= 0 {
+			delimiter = ":"
+		}
+	}
+
+	// sample on shared client to avoid switching database of browser
+	client, err := Connection().acquireClient(s.ctx, server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	defer Connection().releaseClient(server, client)
+	ctx, cancelFunc := context.WithCancel(s.ctx)
+	defer cancelFunc()
+
+	cancelStopEvent := runtime.EventsOnce(ctx, "memreport:stop:"+serialNo, func(data ...any) {
+		cancelFunc()
+	})
+	defer cancelStopEvent()
+
+	var mutex sync.Mutex
+	// reserved is count of keys being sampled by all nodes, reserve before sampling so that
+	// concurrent masters in cluster mode will not exceed the sample size together
+	var sampled, reserved, totalMemory int64
+	groups := map[string]*memoryGroup{}
+	sortedGroups := func() []memoryGroup {
+		ret := make([]memoryGroup, 0, len(groups))
+		for _, g := range groups {
+			ret = append(ret, *g)
+		}
+		sort.Slice(ret, func(i, j int) bool {
+			return ret[i].Memory > ret[j].Memory
+		})
+		return ret
+	}
+	processEvent := "memreport:" + serialNo
+	lastEmit := time.Now()
+	emitProgress := func() {
+		runtime.EventsEmit(ctx, processEvent, map[string]any{
+			"sampled": sampled,
+			"memory":  totalMemory,
+			"groups":  sortedGroups(),
+		})
+	}
+
+	scanSize := int64(Preferences().GetScanSize())
+	// @param limit max keys sampled from this node
+	sample := func(ctx context.Context, cli redis.Cmdable, limit int64) error {
+		var cursor uint64
+		var count int64
+		for {
+			keys, nextCursor, scanErr := cli.Scan(ctx, cursor, "*", scanSize).Result()
+			if scanErr != nil {
+				return scanErr
+			}
+			cursor = nextCursor
+
+			mutex.Lock()
+			remain := min(int64(sampleSize)-reserved, limit-count)
+			if remain <= 0 {
+				mutex.Unlock()
+				return nil
+			}
+			if int64(len(keys)) > remain {
+				keys = keys[:remain]
+			}
+			reserved += int64(len(keys))
+			count += int64(len(keys))
+			mutex.Unlock()
+
+			if len(keys) > 0 {
+				pipe := cli.Pipeline()
+				typeCmds := make([]*redis.StatusCmd, len(keys))
+				memCmds := make([]*redis.IntCmd, len(keys))
+				for i, k := range keys {
+					typeCmds[i] = pipe.Type(ctx, k)
+					memCmds[i] = pipe.MemoryUsage(ctx, k)
+				}
+				// key may be expired or deleted while sampling, ignore error of single command
+				if _, execErr := pipe.Exec(ctx); execErr != nil && ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				mutex.Lock()
+				for i, k := range keys {
+					keyType := typeCmds[i].Val()
+					if keyType == "none" || len(keyType) <= 0 {
+						// give back reservation of missing key
+						reserved, count = reserved-1, count-1
+						continue
+					}
+					var prefix string
+					if idx := strings.Index(k, delimiter); idx >= 0 {
+						prefix = k[:idx]
+					}
+					groupKey := prefix + delimiter + keyType
+					g, ok := groups[groupKey]
+					if !ok {
+						g = &memoryGroup{Prefix: prefix, Type: keyType}
+						groups[groupKey] = g
+					}
+					mem := memCmds[i].Val()
+					g.Count += 1
+					g.Memory += mem
+					totalMemory += mem
+					sampled += 1
+				}
+				if time.Since(lastEmit).Milliseconds() > 100 {
+					lastEmit = time.Now()
+					emitProgress()
+				}
+				mutex.Unlock()
+			}
+
+			if cursor == 0 {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+
+	var totalKeys int64
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// split sample size to each master, avoid sampling from the fastest one only
+		var masterCount int64
+		_ = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			mutex.Lock()
+			masterCount += 1
+			mutex.Unlock()
+			return nil
+		})
+		perMaster := (int64(sampleSize) + max(masterCount, 1) - 1) / max(masterCount, 1)
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			size, _ := cli.DBSize(ctx).Result()
+			mutex.Lock()
+			totalKeys += size
+			mutex.Unlock()
+			return sample(ctx, cli, perMaster)
+		})
+	} else {
+		totalKeys, _ = client.DBSize(ctx).Result()
+		err = sample(ctx, client, int64(sampleSize))
+	}
+	mutex.Lock()
+	emitProgress()
+	mutex.Unlock()
+
+	canceled := errors.Is(err, context.Canceled)
+	if err != nil && !canceled {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Canceled  bool          `json:"canceled"`
+		Sampled   int64         `json:"sampled"`
+		TotalKeys int64         `json:"totalKeys"` // total keys of database, estimate full memory by ratio to sampled
+		Memory    int64         `json:"memory"`    // total memory of sampled keys in bytes
+		Delimiter string        `json:"delimiter"`
+		Groups    []memoryGroup `json:"groups"`
+	}{
+		Canceled:  canceled,
+		Sampled:   sampled,
+		TotalKeys: totalKeys,
+		Memory:    totalMemory,
+		Delimiter: delimiter,
+		Groups:    sortedGroups(),
+	}
+	return
+}
+
+// KeyspaceSummary get count of keys by DBSIZE and count of keys with expiration from INFO keyspace for each database,
+// counts are aggregated from all masters in cluster mode
+func (s *serverService) KeyspaceSummary(server string) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	type dbSummary struct {
+		DB      int   `json:"db"`
+		Keys    int64 `json:"keys"`
+		Expires int64 `json:"expires"`
+		AvgTTL  int64 `json:"avgTTL"` // milliseconds
+	}
+	client, ctx := item.client, item.ctx
+	var summaries []dbSummary
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode only support db0
+		var mutex sync.Mutex
+		var summary dbSummary
+		var nodes int64
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			size, sizeErr := cli.DBSize(ctx).Result()
+			if sizeErr != nil {
+				return sizeErr
+			}
+			var dbInfo map[string]int
+			if res, infoErr := cli.Info(ctx, "keyspace").Result(); infoErr == nil {
+				dbInfo = Browser().parseDBItemInfo(Browser().parseInfo(res)["Keyspace"]["db0"])
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			summary.Keys += size
+			summary.Expires += int64(dbInfo["expires"])
+			summary.AvgTTL += int64(dbInfo["avg_ttl"])
+			nodes += 1
+			return nil
+		})
+		if nodes > 0 {
+			summary.AvgTTL /= nodes
+		}
+		summaries = []dbSummary{summary}
+	} else if rdb, ok := client.(*redis.Client); ok {
+		totalDB := 16
+		if conf, confErr := rdb.ConfigGet(ctx, "databases").Result(); confErr == nil {
+			if total, convErr := strconv.Atoi(conf["databases"]); convErr == nil && total > 0 {
+				totalDB = total
+			}
+		}
+		var keyspace map[string]string
+		if res, infoErr := rdb.Info(ctx, "keyspace").Result(); infoErr == nil {
+			keyspace = Browser().parseInfo(res)["Keyspace"]
+		}
+
+		// switch database on a dedicated connection in one round trip, and switch back finally
+		conn := rdb.Conn()
+		defer conn.Close()
+		sizeCmds := make([]*redis.IntCmd, totalDB)
+		_, err = conn.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for db := 0; db < totalDB; db++ {
+				pipe.Select(ctx, db)
+				sizeCmds[db] = pipe.DBSize(ctx)
+			}
+			pipe.Select(ctx, rdb.Options().DB)
+			return nil
+		})
+		if err == nil {
+			summaries = make([]dbSummary, 0, totalDB)
+			for db := 0; db < totalDB; db++ {
+				dbInfo := Browser().parseDBItemInfo(keyspace["db"+strconv.Itoa(db)])
+				summaries = append(summaries, dbSummary{
+					DB:      db,
+					Keys:    sizeCmds[db].Val(),
+					Expires: int64(dbInfo["expires"]),
+					AvgTTL:  int64(dbInfo["avg_ttl"]),
+				})
+			}
+		}
+	}
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Databases []dbSummary `json:"databases"`
+	}{
+		Databases: summaries,
+	}
+	return
+}
+
+// check confirmation token of destructive operation, it should be typed as the same as server name
+func (s *serverService) checkConfirmToken(server, confirmToken string) error {
+	if len(confirmToken) <= 0 || confirmToken != server {
+		return errors.New("confirmation token mismatch, type the server name to confirm")
+	}
+	return nil
+}
+
+// FlushDB remove all keys of database after confirmed
+// @param confirmToken should be the same as server name
+func (s *serverService) FlushDB(server string, db int, async bool, confirmToken string) (resp types.JSResp) {
+	defer Audit().record(server, db, "flushdb", "", "", &resp)
+
+	if err := s.checkConfirmToken(server, confirmToken); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	log.Printf("flush database %d of server \"%s\", async: %t\n", db, server, async)
+	resp = Browser().flushDB(server, db, async)
+	return
+}
+
+// FlushAll remove all keys of all databases after confirmed
+// @param confirmToken should be the same as server name
+func (s *serverService) FlushAll(server string, async bool, confirmToken string) (resp types.JSResp) {
+	defer Audit().record(server, -1, "flushall", "", "", &resp)
+
+	if err := s.checkConfirmToken(server, confirmToken); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	log.Printf("flush all databases of server \"%s\", async: %t\n", server, async)
+	flush := func(ctx context.Context, cli redis.UniversalClient, async bool) error {
+		if async {
+			return cli.FlushAllAsync(ctx).Err()
+		}
+		return cli.FlushAll(ctx).Err()
+	}
+
+	client, ctx := item.client, item.ctx
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		// cluster mode
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+			return flush(ctx, cli, async)
+		})
+		// try sync mode if error cause
+		if err != nil && async {
+			err = cluster.ForEachMaster(ctx, func(ctx context.Context, cli *redis.Client) error {
+				return flush(ctx, cli, false)
+			})
+		}
+	} else {
+		if err = flush(ctx, client, async); err != nil && async {
+			// try sync mode if error cause
+			err = flush(ctx, client, false)
+		}
+	}
+
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	resp.Success = true
+	return
+}
+
+// check connection is not marked as read-only before modifying replication
+func (s *serverService) checkWritable(server string) error {
+	if conf := Connection().getConnection(server); conf != nil && conf.ReadOnly {
+		return redis2.ErrReadOnly
+	}
+	return nil
+}
+
+// get standalone client of server, replication can not be changed by node in cluster mode
+func (s *serverService) getReplicationClient(server string) (redis.UniversalClient, context.Context, error) {
+	item, err := Browser().getRedisClient(server, -1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := item.client.(*redis.ClusterClient); ok {
+		return nil, nil, errors.New("not supported in cluster mode, use CLUSTER FAILOVER on replica instead")
+	}
+	return item.client, item.ctx, nil
+}
+
+// Failover coordinate a manual failover from master to one of its replicas by FAILOVER, requires Redis 6.2 or later
+// @param confirmToken should be the same as server name
+func (s *serverService) Failover(server string, option types.FailoverOption, confirmToken string) (resp types.JSResp) {
+	args := []any{"failover"}
+	if option.Abort {
+		args = append(args, "abort")
+	} else {
+		if len(option.Host) > 0 {
+			if option.Port <= 0 {
+				resp.Msg = "port of target replica is required"
+				return
+			}
+			args = append(args, "to", option.Host, option.Port)
+			if option.Force {
+				if option.Timeout <= 0 {
+					resp.Msg = "timeout is required for force failover"
+					return
+				}
+				args = append(args, "force")
+			}
+		} else if option.Force {
+			resp.Msg = "target replica is required for force failover"
+			return
+		}
+		if option.Timeout > 0 {
+			args = append(args, "timeout", option.Timeout)
+		}
+	}
+	defer Audit().record(server, -1, "failover", "", strings.TrimSpace(fmt.Sprintln(args[1:]...)), &resp)
+
+	if err := s.checkWritable(server); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if err := s.checkConfirmToken(server, confirmToken); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx, err := s.getReplicationClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	log.Printf("failover server \"%s\": %v\n", server, args)
+	result, err := client.Do(ctx, args...).Text()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "ERR unknown command") {
+			resp.Msg = "FAILOVER requires Redis 6.2 or later"
+		} else {
+			resp.Msg = err.Error()
+		}
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Result string `json:"result"`
+	}{
+		Result: result,
+	}
+	return
+}
+
+// make server replicate with master by REPLICAOF, or promote to master if masterHost is empty
+func (s *serverService) replicaOf(server, masterHost string, masterPort int, confirmToken string) (resp types.JSResp) {
+	host, port := "no", "one"
+	if len(masterHost) > 0 {
+		host, port = masterHost, strconv.Itoa(masterPort)
+	}
+	defer Audit().record(server, -1, "replicaof", "", host+" "+port, &resp)
+
+	if err := s.checkWritable(server); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	if err := s.checkConfirmToken(server, confirmToken); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx, err := s.getReplicationClient(server)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	log.Printf("replicaof of server \"%s\": %s %s\n", server, host, port)
+	result, err := client.Do(ctx, "replicaof", host, port).Text()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Result string `json:"result"`
+	}{
+		Result: result,
+	}
+	return
+}
+
+// ReplicaOf make server a replica of specified master by REPLICAOF
+// @param confirmToken should be the same as server name
+func (s *serverService) ReplicaOf(server, masterHost string, masterPort int, confirmToken string) (resp types.JSResp) {
+	if len(masterHost) <= 0 || masterPort <= 0 {
+		resp.Msg = "master host and port are required"
+		return
+	}
+	return s.replicaOf(server, masterHost, masterPort, confirmToken)
+}
+
+// ReplicaOfNoOne stop replication and promote server to master by REPLICAOF NO ONE
+// @param confirmToken should be the same as server name
+func (s *serverService) ReplicaOfNoOne(server, confirmToken string) (resp types.JSResp) {
+	return s.replicaOf(server, "", 0, confirmToken)
+}
+
+// StartServerStats start polling server metrics periodically, metrics will be emitted by returned event name
+// @param intervalMs polling interval in milliseconds
+func (s *serverService) StartServerStats(server string, intervalMs int) (resp types.JSResp) {
+	if _, err := Browser().getRedisClient(server, -1); err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	interval := time.Duration(intervalMs) * time.Millisecond
+	if intervalMs <= 0 {
+		interval = consts.DEFAULT_SERVER_STATS_INTERVAL * time.Millisecond
+	} else if interval < 200*time.Millisecond {
+		interval = 200 * time.Millisecond
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if item, ok := s.items[server]; ok {
+		// stop previous polling
+		close(item.closeCh)
+	}
+	item := &statsItem{
+		closeCh:   make(chan struct{}),
+		eventName: fmt.Sprintf("server:stats:%s:%d", server, time.Now().UnixMilli()),
+	}
+	s.items[server] = item
+	go s.processStats(server, interval, item.eventName, item.closeCh)
+
+	resp.Success = true
+	resp.Data = struct {
+		EventName string `json:"eventName"`
+	}{
+		EventName: item.eventName,
+	}
+	return
+}
+
+// poll server info on ticker until closed, polling is run in this goroutine so never overlaps
+func (s *serverService) processStats(server string, interval time.Duration, eventName string, closeCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastTime time.Time
+	var lastCommands, lastHits, lastMisses int64
+	poll := func() {
+		stats := serverStats{
+			Timestamp: time.Now().UnixMilli(),
+		}
+		defer func() {
+			runtime.EventsEmit(s.ctx, eventName, stats)
+		}()
+
+		item, err := Browser().getRedisClient(server, -1)
+		if err != nil {
+			stats.Error = err.Error()
+			return
+		}
+		ctx, cancel := context.WithTimeout(item.ctx, max(interval, time.Second))
+		defer cancel()
+		res, err := item.client.Info(ctx).Result()
+		if err != nil {
+			stats.Error = err.Error()
+			return
+		}
+
+		info := s.parseInfo(res)
+		getInt := func(section, key string) int64 {
+			v, _ := info[section][key].(int64)
+			return v
+		}
+		getFloat := func(section, key string) float64 {
+			switch v := info[section][key].(type) {
+			case float64:
+				return v
+			case int64:
+				return float64(v)
+			}
+			return 0
+		}
+		now := time.Now()
+		commands := getInt("stats", "total_commands_processed")
+		hits, misses := getInt("stats", "keyspace_hits"), getInt("stats", "keyspace_misses")
+		if !lastTime.IsZero() {
+			if elapsed := now.Sub(lastTime).Seconds(); elapsed > 0 && commands >= lastCommands {
+				stats.OpsPerSec = float64(commands-lastCommands) / elapsed
+			}
+			if total := (hits - lastHits) + (misses - lastMisses); total > 0 {
+				stats.HitRate = float64(hits-lastHits) / float64(total) * 100
+			}
+		}
+		lastTime, lastCommands, lastHits, lastMisses = now, commands, hits, misses
+
+		stats.InstantOps = getInt("stats", "instantaneous_ops_per_sec")
+		stats.InputKbps = getFloat("stats", "instantaneous_input_kbps")
+		stats.OutputKbps = getFloat("stats", "instantaneous_output_kbps")
+		stats.UsedMemory = getInt("memory", "used_memory")
+		stats.ConnectedClients = getInt("clients", "connected_clients")
+	}
+
+	poll()
+	for {
+		select {
+		case <-ticker.C:
+			poll()
+		case <-closeCh:
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// StopServerStats stop polling server metrics
+func (s *serverService) StopServerStats(server string) (resp types.JSResp) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if item, ok := s.items[server]; ok {
+		close(item.closeCh)
+		delete(s.items, server)
+	}
+	resp.Success = true
+	return
+}
+
+// StopAll stop all server metrics polling
+func (s *serverService) StopAll() {
+	if s.ctxCancel != nil {
+		s.ctxCancel()
+	}
+
+	s.mutex.Lock()
+	servers := make([]string, 0, len(s.items))
+	for server := range s.items {
+		servers = append(servers, server)
+	}
+	s.mutex.Unlock()
+	for _, server := range servers {
+		s.StopServerStats(server)
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strings"
+	"sync"
+	"time"
+	"tinyrdm/backend/types"
+	strutil "tinyrdm/backend/utils/string"
+)
+
+type consumerLag struct {
+	Name              string `json:"name"`
+	Pending           int64  `json:"pending"`                   // count of pending entries by XPENDING
+	Idle              int64  `json:"idle"`                      // milliseconds since last attempted interaction, -1 if unknown
+	OldestPendingID   string `json:"oldestPendingId,omitempty"` // the earliest delivered but not acknowledged entry
+	OldestPendingIdle int64  `json:"oldestPendingIdle"`         // milliseconds since the earliest pending entry delivered
+}
+
+type groupLag struct {
+	Name            string        `json:"name"`
+	LastDeliveredID string        `json:"lastDeliveredId"`
+	EntriesRead     int64         `json:"entriesRead"`
+	Lag             int64         `json:"lag"`   // count of entries not yet delivered to group, -1 if unknown
+	IDLag           int64         `json:"idLag"` // milliseconds between last generated and last delivered id
+	Pending         int64         `json:"pending"`
+	Consumers       []consumerLag `json:"consumers"`
+}
+
+type streamService struct {
+	ctx context.Context
+}
+
+var stream *streamService
+var onceStream sync.Once
+
+func Stream() *streamService {
+	if stream == nil {
+		onceStream.Do(func() {
+			stream = &streamService{}
+		})
+	}
+	return stream
+}
+
+func (s *streamService) Start(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// GroupLag get lag of each consumer group of stream by XINFO STREAM/GROUPS/CONSUMERS,
+// and pending entries of each consumer by XPENDING
+func (s *streamService) GroupLag(server string, db int, k any) (resp types.JSResp) {
+	item, err := Browser().getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	info, err := client.XInfoStream(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+	groups, err := client.XInfoGroups(ctx, key).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	lastMs, _ := Browser().parseStreamID(info.LastGeneratedID)
+	firstMs, _ := Browser().parseStreamID(info.FirstEntry.ID)
+	result := make([]groupLag, 0, len(groups))
+	for _, g := range groups {
+		lag := groupLag{
+			Name:            g.Name,
+			LastDeliveredID: g.LastDeliveredID,
+			EntriesRead:     g.EntriesRead,
+			Pending:         g.Pending,
+			Consumers:       []consumerLag{},
+		}
+		switch {
+		case g.LastDeliveredID == info.LastGeneratedID:
+			lag.Lag = 0
+		case g.Lag > 0:
+			lag.Lag = g.Lag
+		default:
+			// lag is not reported by server before 7.0, or can not be determined after entries deleted
+			lag.Lag = -1
+		}
+		if deliveredMs, _ := Browser().parseStreamID(g.LastDeliveredID); deliveredMs > 0 {
+			lag.IDLag = lastMs - deliveredMs
+		} else if info.Length > 0 {
+			// nothing delivered yet, lag from the first entry
+			lag.IDLag = lastMs - firstMs
+		}
+
+		pending, pendingErr := client.XPending(ctx, key, g.Name).Result()
+		if pendingErr != nil && !errors.Is(pendingErr, redis.Nil) {
+			resp.Msg = pendingErr.Error()
+			return
+		}
+		pendingCounts := map[string]int64{}
+		if pending != nil {
+			for name, count := range pending.Consumers {
+				pendingCounts[name] = count
+			}
+		}
+		if consumers, subErr := client.XInfoConsumers(ctx, key, g.Name).Result(); subErr == nil {
+			for _, c := range consumers {
+				lag.Consumers = append(lag.Consumers, consumerLag{
+					Name:    c.Name,
+					Pending: pendingCounts[c.Name],
+					Idle:    c.Idle.Milliseconds(),
+				})
+				delete(pendingCounts, c.Name)
+			}
+		}
+		// consumers not listed by XINFO CONSUMERS
+		for name, count := range pendingCounts {
+			lag.Consumers = append(lag.Consumers, consumerLag{
+				Name:    name,
+				Pending: count,
+				Idle:    -1,
+			})
+		}
+		for i := range lag.Consumers {
+			if lag.Consumers[i].Pending <= 0 {
+				continue
+			}
+			oldest, extErr := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream:   key,
+				Group:    g.Name,
+				Start:    "-",
+				End:      "+",
+				Count:    1,
+				Consumer: lag.Consumers[i].Name,
+			}).Result()
+			if extErr == nil && len(oldest) > 0 {
+				lag.Consumers[i].OldestPendingID = oldest[0].ID
+				lag.Consumers[i].OldestPendingIdle = oldest[0].Idle.Milliseconds()
+			}
+		}
+		result = append(result, lag)
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Length          int64      `json:"length"`
+		LastGeneratedID string     `json:"lastGeneratedId"`
+		EntriesAdded    int64      `json:"entriesAdded"`
+		Groups          []groupLag `json:"groups"`
+	}{
+		Length:          info.Length,
+		LastGeneratedID: info.LastGeneratedID,
+		EntriesAdded:    info.EntriesAdded,
+		Groups:          result,
+	}
+	return
+}
+
+type claimedEntry struct {
+	ID     string         `json:"id"`
+	Fields map[string]any `json:"fields"`
+}
+
+func (s *streamService) toClaimedEntries(msgs []redis.XMessage) []claimedEntry {
+	entries := make([]claimedEntry, 0, len(msgs))
+	for _, msg := range msgs {
+		entries = append(entries, claimedEntry{
+			ID:     msg.ID,
+			Fields: msg.Values,
+		})
+	}
+	return entries
+}
+
+// AutoClaim transfer ownership of pending entries idle longer than minIdle to consumer by XAUTOCLAIM
+// @param minIdle milliseconds
+// @param count max entries to claim, 0 for default of server
+func (s *streamService) AutoClaim(server string, db int, k any, group, consumer string, minIdle, count int64) (resp types.JSResp) {
+	defer Audit().record(server, db, "claim_stream", strutil.DecodeRedisKey(k), "auto claim to "+group+"/"+consumer, &resp)
+	if minIdle < 0 {
+		resp.Msg = "min idle time should not be negative"
+		return
+	}
+	item, err := Browser().getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	msgs, next, err := client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   key,
+		Group:    group,
+		MinIdle:  time.Duration(minIdle) * time.Millisecond,
+		Start:    "0-0",
+		Count:    count,
+		Consumer: consumer,
+	}).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Entries []claimedEntry `json:"entries"`
+		Next    string         `json:"next"` // id to continue scanning pending entries, "0-0" if all scanned
+	}{
+		Entries: s.toClaimedEntries(msgs),
+		Next:    next,
+	}
+	return
+}
+
+// Claim transfer ownership of specified pending entries idle longer than minIdle to consumer by XCLAIM
+// @param minIdle milliseconds
+func (s *streamService) Claim(server string, db int, k any, group, consumer string, minIdle int64, ids []string) (resp types.JSResp) {
+	defer Audit().record(server, db, "claim_stream", strutil.DecodeRedisKey(k), "claim to "+group+"/"+consumer+", ids "+strings.Join(ids, ","), &resp)
+	if minIdle < 0 {
+		resp.Msg = "min idle time should not be negative"
+		return
+	}
+	if len(ids) <= 0 {
+		resp.Msg = "no entry id specified"
+		return
+	}
+	item, err := Browser().getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	msgs, err := client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   key,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  time.Duration(minIdle) * time.Millisecond,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Entries []claimedEntry `json:"entries"`
+	}{
+		Entries: s.toClaimedEntries(msgs),
+	}
+	return
+}
+
+// Ack acknowledge pending entries of group by XACK
+func (s *streamService) Ack(server string, db int, k any, group string, ids []string) (resp types.JSResp) {
+	defer Audit().record(server, db, "ack_stream", strutil.DecodeRedisKey(k), "group "+group+", ids "+strings.Join(ids, ","), &resp)
+	if len(ids) <= 0 {
+		resp.Msg = "no entry id specified"
+		return
+	}
+	item, err := Browser().getRedisClient(server, db)
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	client, ctx := item.client, item.ctx
+	key := strutil.DecodeRedisKey(k)
+	acked, err := client.XAck(ctx, key, group, ids...).Result()
+	if err != nil {
+		resp.Msg = err.Error()
+		return
+	}
+
+	resp.Success = true
+	resp.Data = struct {
+		Acked int64 `json:"acked"`
+	}{
+		Acked: acked,
+	}
+	return
+}
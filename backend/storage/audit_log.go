@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"tinyrdm/backend/types"
+	"unicode/utf8"
+)
+
+// max length of detail in bytes, longer one will be truncated before written
+const maxAuditDetailLen = 4096
+
+// max length of line to read, longer one will be skipped
+const maxAuditLineLen = 64 * 1024
+
+// AuditLogStorage append-only log file of audit entries, one json object per line
+type AuditLogStorage struct {
+	storage *localStorage
+	mutex   sync.Mutex
+}
+
+func NewAuditLog() *AuditLogStorage {
+	return &AuditLogStorage{
+		storage: NewLocalStore("audit.log"),
+	}
+}
+
+// Append write entries to the end of log file
+func (a *AuditLogStorage) Append(entries ...types.AuditEntry) error {
+	var content []byte
+	for _, entry := range entries {
+		entry.Detail = truncateDetail(entry.Detail)
+		b, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		content = append(append(content, b...), '\n')
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if err := ensureDirExists(path.Dir(a.storage.ConfPath)); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(a.storage.ConfPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// tighten permission of log file created by earlier version
+	_ = file.Chmod(0600)
+	_, err = file.Write(content)
+	return err
+}
+
+// truncate detail to max length without breaking utf-8 characters
+func truncateDetail(detail string) string {
+	if len(detail) <= maxAuditDetailLen {
+		return detail
+	}
+	end := maxAuditDetailLen
+	for end > 0 && !utf8.RuneStart(detail[end]) {
+		end--
+	}
+	return detail[:end] + "..."
+}
+
+// read next line, the line longer than max length is discarded and returned as nil
+func readAuditLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+	var tooLong bool
+	for {
+		part, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		if !tooLong {
+			if len(line)+len(part) > maxAuditLineLen {
+				tooLong, line = true, nil
+			} else {
+				line = append(line, part...)
+			}
+		}
+		if !isPrefix {
+			if tooLong {
+				return nil, nil
+			}
+			return line, nil
+		}
+	}
+}
+
+// GetRecent get the latest entries in reverse order, malformed or over-long lines will be skipped
+// @param server filter by server name, empty for all
+// @param limit max count of entries
+func (a *AuditLogStorage) GetRecent(server string, limit int) ([]types.AuditEntry, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	file, err := os.Open(a.storage.ConfPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []types.AuditEntry{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	// keep the latest entries in ring buffer while reading
+	ring := make([]types.AuditEntry, 0, limit)
+	var next int
+	reader := bufio.NewReader(file)
+	for {
+		line, err := readAuditLine(reader)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		var entry types.AuditEntry
+		if line == nil || json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		if len(server) > 0 && entry.Server != server {
+			continue
+		}
+		if len(ring) < limit {
+			ring = append(ring, entry)
+		} else {
+			ring[next] = entry
+		}
+		next = (next + 1) % limit
+	}
+
+	entries := make([]types.AuditEntry, 0, len(ring))
+	for i := 1; i <= len(ring); i++ {
+		entries = append(entries, ring[(next-i+len(ring))%len(ring)])
+	}
+	return entries, nil
+}
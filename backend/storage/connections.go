@@ -41,6 +41,7 @@ func (c *ConnectionsStorage) defaultConnectionItem() types.ConnectionConfig {
 		LoadSize:        consts.DEFAULT_LOAD_SIZE,
 		MarkColor:       "",
 		RefreshInterval: 5,
+		Protocol:        2,
 		Sentinel: types.ConnectionSentinel{
 			Master: "mymaster",
 		},
@@ -162,6 +163,9 @@ func (c *ConnectionsStorage) CreateConnection(param types.ConnectionConfig) erro
 		if len(param.Group) > 0 {
 			// no group matched, create new group
 			conns = append(conns, types.Connection{
+				ConnectionConfig: types.ConnectionConfig{
+					Name: param.Group,
+				},
 				Type: "group",
 				Connections: types.Connections{
 					types.Connection{
@@ -336,20 +340,73 @@ func (c *ConnectionsStorage) RenameGroup(name, newName string) error {
 	return c.saveConnections(conns)
 }
 
-// DeleteGroup remove specified group, include all connections under it
-func (c *ConnectionsStorage) DeleteGroup(group string, includeConnection bool) error {
+// DeleteGroup remove specified group
+// if cascade is true, all connections under it will be removed too,
+// otherwise only an empty group can be removed
+func (c *ConnectionsStorage) DeleteGroup(group string, cascade bool) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
 	conns := c.getConnections()
 	for i, conn := range conns {
 		if conn.Type == "group" && conn.Name == group {
-			conns = append(conns[:i], conns[i+1:]...)
-			if includeConnection {
-				conns = append(conns, conn.Connections...)
+			if !cascade && len(conn.Connections) > 0 {
+				return errors.New("group is not empty")
 			}
+			conns = append(conns[:i], conns[i+1:]...)
 			return c.saveConnections(conns)
 		}
 	}
 	return errors.New("group not found")
 }
+
+// MoveConnection move connection into specified group, move to root level if group is empty
+func (c *ConnectionsStorage) MoveConnection(name, group string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	conns := c.getConnections()
+	groupIndex := -1
+	if len(group) > 0 {
+		groupIndex = slices.IndexFunc(conns, func(conn types.Connection) bool {
+			return conn.Type == "group" && conn.Name == group
+		})
+		if groupIndex == -1 {
+			return errors.New("group not found")
+		}
+	}
+
+	// take connection out from its current position
+	var target *types.Connection
+	for i, conn := range conns {
+		if conn.Type == "group" {
+			for j, subConn := range conn.Connections {
+				if subConn.Name == name {
+					target = &subConn
+					conns[i].Connections = append(conns[i].Connections[:j], conns[i].Connections[j+1:]...)
+					break
+				}
+			}
+		} else if conn.Name == name {
+			target = &conn
+			conns = append(conns[:i], conns[i+1:]...)
+			if groupIndex > i {
+				groupIndex -= 1
+			}
+		}
+		if target != nil {
+			break
+		}
+	}
+	if target == nil {
+		return errors.New("no match connection")
+	}
+
+	target.Group = ""
+	if groupIndex >= 0 {
+		conns[groupIndex].Connections = append(conns[groupIndex].Connections, *target)
+	} else {
+		conns = append(conns, *target)
+	}
+	return c.saveConnections(conns)
+}
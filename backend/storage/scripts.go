@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"errors"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"sync"
+	"time"
+	"tinyrdm/backend/types"
+)
+
+type ScriptsStorage struct {
+	storage *localStorage
+	mutex   sync.Mutex
+}
+
+func NewScripts() *ScriptsStorage {
+	return &ScriptsStorage{
+		storage: NewLocalStore("scripts.yaml"),
+	}
+}
+
+func (s *ScriptsStorage) getScripts() (ret types.Scripts) {
+	b, err := s.storage.Load()
+	ret = types.Scripts{}
+	if err != nil {
+		return
+	}
+
+	if err = yaml.Unmarshal(b, &ret); err != nil {
+		ret = types.Scripts{}
+	}
+	return
+}
+
+func (s *ScriptsStorage) saveScripts(scripts types.Scripts) error {
+	b, err := yaml.Marshal(&scripts)
+	if err != nil {
+		return err
+	}
+	return s.storage.Store(b)
+}
+
+// GetScripts get all saved scripts from local
+func (s *ScriptsStorage) GetScripts() types.Scripts {
+	return s.getScripts()
+}
+
+// GetScript get saved script by name
+func (s *ScriptsStorage) GetScript(name string) *types.Script {
+	scripts := s.getScripts()
+	for i := range scripts {
+		if scripts[i].Name == name {
+			return &scripts[i]
+		}
+	}
+	return nil
+}
+
+// SaveScript create new script or replace the body of existing one with the same name
+func (s *ScriptsStorage) SaveScript(name, body string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	scripts := s.getScripts()
+	script := types.Script{
+		Name:      name,
+		Body:      body,
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+	if idx := slices.IndexFunc(scripts, func(sc types.Script) bool {
+		return sc.Name == name
+	}); idx >= 0 {
+		scripts[idx] = script
+	} else {
+		scripts = append(scripts, script)
+	}
+	return s.saveScripts(scripts)
+}
+
+// DeleteScript remove saved script by name
+func (s *ScriptsStorage) DeleteScript(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	scripts := s.getScripts()
+	idx := slices.IndexFunc(scripts, func(sc types.Script) bool {
+		return sc.Name == name
+	})
+	if idx < 0 {
+		return errors.New("no match script")
+	}
+	scripts = slices.Delete(scripts, idx, idx+1)
+	return s.saveScripts(scripts)
+}
@@ -0,0 +1,12 @@
+package types
+
+type AuditEntry struct {
+	Time      int64  `json:"time"` // milliseconds
+	Server    string `json:"server"`
+	DB        int    `json:"db"` // -1 if not specified database
+	Key       string `json:"key,omitempty"`
+	Operation string `json:"operation"`        // set/delete/delete_pattern/rename/flushdb/flushall
+	Detail    string `json:"detail,omitempty"` // extra info like target name of rename
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
@@ -22,6 +22,12 @@ type ConnectionConfig struct {
 	LoadSize        int                `json:"loadSize,omitempty" yaml:"load_size,omitempty"`
 	MarkColor       string             `json:"markColor,omitempty" yaml:"mark_color,omitempty"`
 	RefreshInterval int                `json:"refreshInterval,omitempty" yaml:"refresh_interval,omitempty"`
+	ReadOnly        bool               `json:"readOnly,omitempty" yaml:"read_only,omitempty"`
+	PoolSize        int                `json:"poolSize,omitempty" yaml:"pool_size,omitempty"`          // max connections in pool, 0 means default of go-redis
+	MinIdleConns    int                `json:"minIdleConns,omitempty" yaml:"min_idle_conns,omitempty"` // min idle connections kept in pool
+	MaxConnAge      int                `json:"maxConnAge,omitempty" yaml:"max_conn_age,omitempty"`     // max lifetime of connection in seconds, 0 means no limit
+	Protocol        int                `json:"protocol,omitempty" yaml:"protocol,omitempty"`           // RESP protocol version 2 or 3, default is 2
+	AuditLog        bool               `json:"auditLog,omitempty" yaml:"audit_log,omitempty"`          // record mutating operations to local audit log
 	Alias           map[int]string     `json:"alias,omitempty" yaml:"alias,omitempty"`
 	SSL             ConnectionSSL      `json:"ssl,omitempty" yaml:"ssl,omitempty"`
 	SSH             ConnectionSSH      `json:"ssh,omitempty" yaml:"ssh,omitempty"`
@@ -52,6 +58,9 @@ type ConnectionSSL struct {
 	KeyFile       string `json:"keyFile,omitempty" yaml:"keyfile,omitempty"`
 	CertFile      string `json:"certFile,omitempty" yaml:"certfile,omitempty"`
 	CAFile        string `json:"caFile,omitempty" yaml:"cafile,omitempty"`
+	KeyPEM        string `json:"keyPem,omitempty" yaml:"key_pem,omitempty"`   // PEM-encoded key, prior to KeyFile
+	CertPEM       string `json:"certPem,omitempty" yaml:"cert_pem,omitempty"` // PEM-encoded certificate, prior to CertFile
+	CAPEM         string `json:"caPem,omitempty" yaml:"ca_pem,omitempty"`     // PEM-encoded CA certificates, prior to CAFile
 	AllowInsecure bool   `json:"allowInsecure,omitempty" yaml:"allow_insecure,omitempty"`
 	SNI           string `json:"sni,omitempty" yaml:"sni,omitempty"`
 }
@@ -68,10 +77,11 @@ type ConnectionSSH struct {
 }
 
 type ConnectionSentinel struct {
-	Enable   bool   `json:"enable,omitempty" yaml:"enable,omitempty"`
-	Master   string `json:"master,omitempty" yaml:"master,omitempty"`
-	Username string `json:"username,omitempty" yaml:"username,omitempty"`
-	Password string `json:"password,omitempty" yaml:"password,omitempty"`
+	Enable   bool     `json:"enable,omitempty" yaml:"enable,omitempty"`
+	Addrs    []string `json:"addrs,omitempty" yaml:"addrs,omitempty"` // additional sentinel nodes("host:port")
+	Master   string   `json:"master,omitempty" yaml:"master,omitempty"`
+	Username string   `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string   `json:"password,omitempty" yaml:"password,omitempty"`
 }
 
 type ConnectionCluster struct {
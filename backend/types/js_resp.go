@@ -1,8 +1,12 @@
 package types
 
+// RESP_CODE_KEY_NOT_EXISTS key is expired or deleted before loaded
+const RESP_CODE_KEY_NOT_EXISTS = "key_not_exists"
+
 type JSResp struct {
 	Success bool   `json:"success"`
 	Msg     string `json:"msg"`
+	Code    string `json:"code,omitempty"` // distinct failure reason for frontend
 	Data    any    `json:"data,omitempty"`
 }
 
@@ -42,14 +46,16 @@ type KeyDetail struct {
 }
 
 type SetKeyParam struct {
-	Server  string `json:"server"`
-	DB      int    `json:"db"`
-	Key     any    `json:"key"`
-	KeyType string `json:"keyType"`
-	Value   any    `json:"value"`
-	TTL     int64  `json:"ttl"`
-	Format  string `json:"format,omitempty"`
-	Decode  string `json:"decode,omitempty"`
+	Server       string `json:"server"`
+	DB           int    `json:"db"`
+	Key          any    `json:"key"`
+	KeyType      string `json:"keyType"`
+	Value        any    `json:"value"`
+	TTL          int64  `json:"ttl"`
+	Format       string `json:"format,omitempty"`
+	Decode       string `json:"decode,omitempty"`
+	WaitReplicas int    `json:"waitReplicas,omitempty"` // wait for write acknowledged by replicas if > 0
+	WaitTimeout  int64  `json:"waitTimeout,omitempty"`  // timeout of waiting in milliseconds
 }
 
 type SetListParam struct {
@@ -110,3 +116,18 @@ type GetHashParam struct {
 	Format string `json:"format,omitempty"`
 	Decode string `json:"decode,omitempty"`
 }
+
+type GeoSearchParam struct {
+	Server    string  `json:"server"`
+	DB        int     `json:"db"`
+	Key       any     `json:"key"`
+	Member    string  `json:"member,omitempty"` // search from member position, prior to Longitude/Latitude
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+	Radius    float64 `json:"radius,omitempty"` // search by radius if greater than 0, otherwise by box
+	Width     float64 `json:"width,omitempty"`
+	Height    float64 `json:"height,omitempty"`
+	Unit      string  `json:"unit,omitempty"` // m/km/mi/ft, default is m
+	Count     int     `json:"count,omitempty"`
+	Desc      bool    `json:"desc,omitempty"`
+}
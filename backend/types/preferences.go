@@ -59,6 +59,7 @@ type PreferencesGeneral struct {
 	FontFamily      []string `json:"fontFamily" yaml:"font_family,omitempty"`
 	FontSize        int      `json:"fontSize" yaml:"font_size"`
 	ScanSize        int      `json:"scanSize" yaml:"scan_size"`
+	ScanAutoTune    bool     `json:"scanAutoTune" yaml:"scan_auto_tune,omitempty"`
 	KeyIconStyle    int      `json:"keyIconStyle" yaml:"key_icon_style"`
 	UseSysProxy     bool     `json:"useSysProxy" yaml:"use_sys_proxy,omitempty"`
 	UseSysProxyHttp bool     `json:"useSysProxyHttp" yaml:"use_sys_proxy_http,omitempty"`
@@ -0,0 +1,18 @@
+package types
+
+type SubscribeOption struct {
+	Filter      string `json:"filter,omitempty"`      // only emit messages which payload matches filter
+	Regex       bool   `json:"regex,omitempty"`       // treat filter as regular expression instead of substring
+	DedupWindow int    `json:"dedupWindow,omitempty"` // suppress identical messages within window in milliseconds, 0 means disabled
+	JSONFormat  string `json:"jsonFormat,omitempty"`  // try to parse payload as JSON and format by "pretty" or "minify", empty means disabled
+
+	ChannelsKey     string `json:"channelsKey,omitempty"`     // read channels from set or list key instead, and keep in sync with it
+	ChannelsKeyDB   int    `json:"channelsKeyDb,omitempty"`   // database of ChannelsKey
+	ChannelsRefresh int    `json:"channelsRefresh,omitempty"` // interval of re-reading ChannelsKey in milliseconds
+}
+
+const MESSAGE_ENCODING_TEXT = "text"
+const MESSAGE_ENCODING_BASE64 = "base64"
+
+const JSON_FORMAT_PRETTY = "pretty"
+const JSON_FORMAT_MINIFY = "minify"
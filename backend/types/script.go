@@ -0,0 +1,9 @@
+package types
+
+type Script struct {
+	Name      string `json:"name" yaml:"name"`
+	Body      string `json:"body" yaml:"body"`
+	UpdatedAt int64  `json:"updatedAt" yaml:"updated_at"` // milliseconds
+}
+
+type Scripts []Script
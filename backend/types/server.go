@@ -0,0 +1,9 @@
+package types
+
+type FailoverOption struct {
+	Host    string `json:"host,omitempty"` // target replica of FAILOVER TO, empty means any replica
+	Port    int    `json:"port,omitempty"`
+	Force   bool   `json:"force,omitempty"`   // failover even if target replica is not caught up, requires Host and Timeout
+	Abort   bool   `json:"abort,omitempty"`   // abort an ongoing failover, other options are ignored
+	Timeout int    `json:"timeout,omitempty"` // milliseconds, 0 means no timeout
+}
@@ -0,0 +1,157 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/proxy"
+)
+
+const sshKeepAliveInterval = 30 * time.Second
+
+// SSHTunnel dial connections through a ssh server.
+// the ssh session is established on first dial, kept alive while any tunneled
+// connection is open, and torn down after the last one closed
+type SSHTunnel struct {
+	addr    string            // ssh server address
+	config  *ssh.ClientConfig // ssh client config
+	forward proxy.Dialer      // forwarding Dialer to ssh server, nil for direct
+
+	mutex  sync.Mutex
+	client *ssh.Client
+	conns  int // count of opening tunneled connections
+}
+
+type tunnelConn struct {
+	net.Conn
+	tunnel    *SSHTunnel
+	closeOnce sync.Once
+}
+
+func (c *tunnelConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(c.tunnel.release)
+	return err
+}
+
+func NewSSHTunnel(addr string, config *ssh.ClientConfig, forward proxy.Dialer) *SSHTunnel {
+	return &SSHTunnel{
+		addr:    addr,
+		config:  config,
+		forward: forward,
+	}
+}
+
+// connect to ssh server, should be called with mutex locked
+func (t *SSHTunnel) connect() (*ssh.Client, error) {
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	var client *ssh.Client
+	if t.forward != nil {
+		// ssh with proxy
+		conn, err := t.forward.Dial("tcp", t.addr)
+		if err != nil {
+			return nil, err
+		}
+		sc, chans, reqs, err := ssh.NewClientConn(conn, t.addr, t.config)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		client = ssh.NewClient(sc, chans, reqs)
+	} else {
+		// ssh without proxy
+		var err error
+		if client, err = ssh.Dial("tcp", t.addr, t.config); err != nil {
+			return nil, err
+		}
+	}
+	t.client = client
+	go t.keepAlive(client)
+	return client, nil
+}
+
+// send keepalive request periodically until ssh client closed
+func (t *SSHTunnel) keepAlive(client *ssh.Client) {
+	done := make(chan struct{})
+	go func() {
+		client.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(sshKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				t.closeClient(client)
+				return
+			}
+		case <-done:
+			t.closeClient(client)
+			return
+		}
+	}
+}
+
+func (t *SSHTunnel) closeClient(client *ssh.Client) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.client == client {
+		t.client = nil
+	}
+	client.Close()
+}
+
+// release one tunneled connection, close ssh client if no connection left
+func (t *SSHTunnel) release() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.conns -= 1; t.conns <= 0 {
+		t.conns = 0
+		if t.client != nil {
+			t.client.Close()
+			t.client = nil
+		}
+	}
+}
+
+func (t *SSHTunnel) Dial(network, addr string) (net.Conn, error) {
+	return t.DialContext(context.Background(), network, addr)
+}
+
+func (t *SSHTunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	client, err := t.connect()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.DialContext(ctx, network, addr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		// ssh session may be broken, reconnect and try again
+		client.Close()
+		t.client = nil
+		if client, err = t.connect(); err != nil {
+			return nil, err
+		}
+		if conn, err = client.DialContext(ctx, network, addr); err != nil {
+			return nil, err
+		}
+	}
+	t.conns += 1
+	return &tunnelConn{
+		Conn:   conn,
+		tunnel: t,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net"
+)
+
+// ConnErrorHook report errors of dropped connection, errors replied by server are ignored
+type ConnErrorHook struct {
+	onError func(err error)
+}
+
+func NewConnErrorHook(onError func(err error)) *ConnErrorHook {
+	return &ConnErrorHook{
+		onError: onError,
+	}
+}
+
+// check if error is caused by broken connection
+func IsConnError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, redis.ErrClosed) || errors.As(err, &netErr)
+}
+
+func (h *ConnErrorHook) report(err error) {
+	if IsConnError(err) && !errors.Is(err, redis.ErrClosed) {
+		h.onError(err)
+	}
+}
+
+func (h *ConnErrorHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		h.report(err)
+		return conn, err
+	}
+}
+
+func (h *ConnErrorHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		h.report(err)
+		return err
+	}
+}
+
+func (h *ConnErrorHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		h.report(err)
+		return err
+	}
+}
@@ -0,0 +1,17 @@
+package redis
+
+import "strings"
+
+var globEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`?`, `\?`,
+	`[`, `\[`,
+	`]`, `\]`,
+)
+
+// EscapeGlob escape glob special characters of literal key name, the result
+// can be used as match pattern of SCAN or KEYS to match the key only
+func EscapeGlob(literal string) string {
+	return globEscaper.Replace(literal)
+}
@@ -0,0 +1,36 @@
+package redis
+
+import "strings"
+
+const SlotCount = 16384
+
+// crc16 by CCITT/XMODEM (polynomial 0x1021), as used by redis cluster
+func crc16(data string) uint16 {
+	var crc uint16
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// HashTag get hash tag of key, returns the whole key if no valid hash tag
+func HashTag(key string) string {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
+
+// HashSlot get slot of key in cluster
+func HashSlot(key string) int {
+	return int(crc16(HashTag(key)) % SlotCount)
+}
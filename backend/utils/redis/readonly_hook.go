@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"net"
+	"strings"
+)
+
+var ErrReadOnly = errors.New("connection is read-only")
+
+// commands which may modify data
+var writeCommands = map[string]struct{}{
+	"append": {}, "bitfield": {}, "bitop": {}, "blmove": {}, "blmpop": {}, "blpop": {}, "brpop": {},
+	"brpoplpush": {}, "bzmpop": {}, "bzpopmax": {}, "bzpopmin": {}, "copy": {}, "decr": {}, "decrby": {},
+	"del": {}, "eval": {}, "evalsha": {}, "expire": {}, "expireat": {}, "fcall": {}, "flushall": {},
+	"flushdb": {}, "geoadd": {}, "georadius": {}, "georadiusbymember": {}, "geosearchstore": {},
+	"getdel": {}, "getex": {}, "getset": {}, "hdel": {}, "hexpire": {}, "hexpireat": {}, "hgetdel": {},
+	"hgetex": {}, "hincrby": {}, "hincrbyfloat": {}, "hmset": {}, "hpersist": {}, "hpexpire": {},
+	"hpexpireat": {}, "hset": {}, "hsetex": {}, "hsetnx": {}, "incr": {}, "incrby": {}, "incrbyfloat": {},
+	"linsert": {}, "lmove": {}, "lmpop": {}, "lpop": {}, "lpush": {}, "lpushx": {}, "lrem": {}, "lset": {},
+	"ltrim": {}, "migrate": {}, "move": {}, "mset": {}, "msetnx": {}, "persist": {}, "pexpire": {},
+	"pexpireat": {}, "pfadd": {}, "pfmerge": {}, "psetex": {}, "rename": {}, "renamenx": {}, "restore": {},
+	"restore-asking": {}, "rpop": {}, "rpoplpush": {}, "rpush": {},
+	"rpushx": {}, "sadd": {}, "sdiffstore": {}, "set": {}, "setbit": {}, "setex": {}, "setnx": {},
+	"setrange": {}, "sinterstore": {}, "smove": {}, "sort": {}, "spop": {}, "srem": {}, "sunionstore": {},
+	"swapdb": {}, "unlink": {}, "xack": {}, "xadd": {}, "xautoclaim": {}, "xclaim": {}, "xdel": {},
+	"xgroup": {}, "xreadgroup": {}, "xsetid": {}, "xtrim": {}, "zadd": {}, "zdiffstore": {}, "zincrby": {},
+	"zinterstore": {}, "zmpop": {}, "zpopmax": {}, "zpopmin": {}, "zrangestore": {}, "zrem": {},
+	"zremrangebylex": {}, "zremrangebyrank": {}, "zremrangebyscore": {}, "zunionstore": {},
+	"json.arrappend": {}, "json.arrinsert": {}, "json.arrpop": {}, "json.arrtrim": {}, "json.clear": {},
+	"json.del": {}, "json.forget": {}, "json.merge": {}, "json.mset": {}, "json.numincrby": {},
+	"json.nummultby": {}, "json.set": {}, "json.strappend": {}, "json.toggle": {},
+	"bf.add": {}, "bf.insert": {}, "bf.loadchunk": {}, "bf.madd": {}, "bf.reserve": {},
+	"cf.add": {}, "cf.addnx": {}, "cf.del": {}, "cf.insert": {}, "cf.insertnx": {}, "cf.loadchunk": {}, "cf.reserve": {},
+	"cms.incrby": {}, "cms.initbydim": {}, "cms.initbyprob": {}, "cms.merge": {},
+	"debug": {}, "failover": {}, "replicaof": {}, "slaveof": {}, "shutdown": {},
+}
+
+// commands with subcommands which may modify data
+var writeSubCommands = map[string][]string{
+	"acl":      {"deluser", "load", "save", "setuser"},
+	"client":   {"kill"},
+	"cluster":  {"addslots", "addslotsrange", "delslots", "delslotsrange", "failover", "flushslots", "forget", "meet", "replicate", "reset", "setslot"},
+	"config":   {"resetstat", "rewrite", "set"},
+	"function": {"delete", "flush", "load", "restore"},
+	"script":   {"flush"},
+}
+
+// IsWriteCommand check if command(with arguments) may modify data
+func IsWriteCommand(args []any) bool {
+	if len(args) <= 0 {
+		return false
+	}
+	name := strings.ToLower(fmt.Sprint(args[0]))
+	if _, ok := writeCommands[name]; ok {
+		return true
+	}
+	if subs, ok := writeSubCommands[name]; ok && len(args) > 1 {
+		sub := strings.ToLower(fmt.Sprint(args[1]))
+		for _, s := range subs {
+			if s == sub {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadOnlyHook reject all write commands before sending to server
+type ReadOnlyHook struct{}
+
+func NewReadOnlyHook() *ReadOnlyHook {
+	return &ReadOnlyHook{}
+}
+
+func (r *ReadOnlyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (r *ReadOnlyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if IsWriteCommand(cmd.Args()) {
+			cmd.SetErr(ErrReadOnly)
+			return ErrReadOnly
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (r *ReadOnlyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if IsWriteCommand(cmd.Args()) {
+				// reject whole pipeline if any write command included
+				for _, c := range cmds {
+					c.SetErr(ErrReadOnly)
+				}
+				return ErrReadOnly
+			}
+		}
+		return next(ctx, cmds)
+	}
+}
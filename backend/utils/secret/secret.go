@@ -0,0 +1,83 @@
+package secretutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const keychainPrefix = "keychain:"
+
+// Resolve resolve secret value which may reference to other place
+// "$NAME" or "${NAME}" read from environment variable NAME, returned as literal if NAME is not set,
+// so that literal password start with "$" still works
+// "keychain:service/account" read from system keychain
+// other value will be returned as literal
+func Resolve(value string) (string, error) {
+	if name, ok := envName(value); ok {
+		if secret, ok := os.LookupEnv(name); ok {
+			return secret, nil
+		}
+		return value, nil
+	}
+
+	if strings.HasPrefix(value, keychainPrefix) {
+		service, account, _ := strings.Cut(strings.TrimPrefix(value, keychainPrefix), "/")
+		if len(service) <= 0 {
+			return "", errors.New("invalid keychain reference, should be \"keychain:service/account\"")
+		}
+		return readKeychain(service, account)
+	}
+
+	return value, nil
+}
+
+// get name of environment variable referenced like "$NAME" or "${NAME}"
+func envName(value string) (string, bool) {
+	name, ok := strings.CutPrefix(value, "$")
+	if !ok {
+		return "", false
+	}
+	if strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}") {
+		name = name[1 : len(name)-1]
+	}
+	if len(name) <= 0 {
+		return "", false
+	}
+	for i, c := range name {
+		if c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(i > 0 && c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// read password from system keychain
+func readKeychain(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		args := []string{"find-generic-password", "-s", service, "-w"}
+		if len(account) > 0 {
+			args = append(args, "-a", account)
+		}
+		cmd = exec.Command("security", args...)
+	case "linux":
+		args := []string{"lookup", "service", service}
+		if len(account) > 0 {
+			args = append(args, "account", account)
+		}
+		cmd = exec.Command("secret-tool", args...)
+	default:
+		return "", fmt.Errorf("keychain is not supported on %s", runtime.GOOS)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("read keychain \"%s\" fail: %s", service, err.Error())
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
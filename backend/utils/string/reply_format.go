@@ -0,0 +1,93 @@
+package strutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatReply format command reply like redis-cli
+func FormatReply(value any) string {
+	if s, ok := value.(string); ok {
+		// simple status reply can not be distinguished from bulk string, show common status without quotes
+		switch s {
+		case "OK", "PONG", "QUEUED":
+			return s
+		}
+	}
+	return strings.Join(formatReplyLines(value), "\n")
+}
+
+func formatReplyLines(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return []string{"(nil)"}
+	case error:
+		return []string{"(error) " + v.Error()}
+	case string:
+		return []string{strconv.Quote(v)}
+	case []byte:
+		return []string{strconv.Quote(string(v))}
+	case int64:
+		return []string{"(integer) " + strconv.FormatInt(v, 10)}
+	case int:
+		return []string{"(integer) " + strconv.Itoa(v)}
+	case float64:
+		return []string{"(double) " + strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		if v {
+			return []string{"(true)"}
+		}
+		return []string{"(false)"}
+	case []any:
+		if len(v) <= 0 {
+			return []string{"(empty array)"}
+		}
+		width := len(strconv.Itoa(len(v)))
+		var lines []string
+		for i, item := range v {
+			lines = appendIndented(lines, fmt.Sprintf("%*d) ", width, i+1), formatReplyLines(item))
+		}
+		return lines
+	case []string:
+		items := make([]any, len(v))
+		for i := range v {
+			items[i] = v[i]
+		}
+		return formatReplyLines(items)
+	case map[any]any:
+		if len(v) <= 0 {
+			return []string{"(empty hash)"}
+		}
+		keys := make([]any, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		width := len(strconv.Itoa(len(keys)))
+		var lines []string
+		for i, k := range keys {
+			prefix := fmt.Sprintf("%*d# %s => ", width, i+1, strings.Join(formatReplyLines(k), " "))
+			lines = appendIndented(lines, prefix, formatReplyLines(v[k]))
+		}
+		return lines
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// append lines with prefix at first line, and indent the rest lines to align
+func appendIndented(lines []string, prefix string, subLines []string) []string {
+	indent := strings.Repeat(" ", len(prefix))
+	for i, line := range subLines {
+		if i == 0 {
+			lines = append(lines, prefix+line)
+		} else {
+			lines = append(lines, indent+line)
+		}
+	}
+	return lines
+}
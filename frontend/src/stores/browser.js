@@ -13,7 +13,6 @@ import {
     DeleteKeys,
     DeleteKeysByPattern,
     ExportKey,
-    FlushDB,
     GetClientList,
     GetCmdHistory,
     GetHashValue,
@@ -38,6 +37,7 @@ import {
     UpdateSetItem,
     UpdateZSetValue,
 } from 'wailsjs/go/services/browserService.js'
+import { FlushAll, FlushDB } from 'wailsjs/go/services/serverService.js'
 import useTabStore from 'stores/tab.js'
 import { nativeRedisKey } from '@/utils/key_convert.js'
 import { BrowserTabType } from '@/consts/browser_tab_type.js'
@@ -45,7 +45,7 @@ import { KeyViewType } from '@/consts/key_view_type.js'
 import { ConnectionType } from '@/consts/connection_type.js'
 import useConnectionStore from 'stores/connections.js'
 import { decodeTypes, formatTypes } from '@/consts/value_view_type.js'
-import { isRedisGlob } from '@/utils/glob_pattern.js'
+import { escapeRedisGlob, isRedisGlob } from '@/utils/glob_pattern.js'
 import { i18nGlobal } from '@/utils/i18n.js'
 import { EventsEmit, EventsOn } from 'wailsjs/runtime/runtime.js'
 import { RedisNodeItem } from '@/objects/redisNodeItem.js'
@@ -393,7 +393,7 @@ const useBrowserStore = defineStore('browser', {
             try {
                 const tab = useTabStore()
                 if (!isEmpty(key)) {
-                    const { data, success, msg } = await GetKeySummary({
+                    const { data, success, msg, code } = await GetKeySummary({
                         server,
                         db,
                         key,
@@ -415,13 +415,13 @@ const useBrowserStore = defineStore('browser', {
                             clearValue,
                         })
                         return
+                    } else if (code === 'key_not_exists') {
+                        // key expired or deleted, just remove from tree view
+                        await this.deleteKey(server, db, key, true)
                     } else {
                         if (!isEmpty(msg)) {
                             $message.error('load key summary fail: ' + msg)
                         }
-                        // its danger to delete "non-exists" key, just remove from tree view
-                        // await this.deleteKey(server, db, key, true)
-                        // TODO: show key not found page or check exists on server first?
                     }
                 }
 
@@ -535,7 +535,7 @@ const useBrowserStore = defineStore('browser', {
                     tab.updateLoading({ server, db, loading: true })
                 }
                 const [storeFormat, storeDecode] = serverInst.getDecodeHistory(key, db)
-                const { data, success, msg } = await GetKeyDetail({
+                const { data, success, msg, code } = await GetKeyDetail({
                     server,
                     db,
                     key,
@@ -567,6 +567,9 @@ const useBrowserStore = defineStore('browser', {
                         matchPattern: retMatch || '',
                         end,
                     })
+                } else if (code === 'key_not_exists') {
+                    // key expired or deleted after loaded summary, just remove from tree view
+                    await this.deleteKey(server, db, key, true)
                 } else {
                     $message.error('load key detail fail:' + msg)
                 }
@@ -707,7 +710,8 @@ const useBrowserStore = defineStore('browser', {
             if (isEmpty(prefix)) {
                 return
             }
-            let match = prefix
+            // prefix may contain glob characters like "user[1]:"
+            let match = escapeRedisGlob(prefix)
             const separator = this.getSeparator(server)
             if (!isEmpty(match)) {
                 if (!endsWith(match, separator)) {
@@ -1702,7 +1706,7 @@ const useBrowserStore = defineStore('browser', {
             try {
                 let deleteCount = 1
                 if (soft !== true) {
-                    const { data } = await DeleteKey(server, db, key)
+                    const { data } = await DeleteKey(server, db, key, false, 0, 0)
                     deleteCount = get(data, 'deleteCount', 0)
                 }
 
@@ -1803,8 +1807,12 @@ const useBrowserStore = defineStore('browser', {
             let deleted = []
             let failCount = 0
             let canceled = false
+            const serialNo = Date.now().valueOf().toString()
+            msgRef.onClose = () => {
+                EventsEmit('delete:stop:' + serialNo)
+            }
             try {
-                const { success, msg, data } = await DeleteKeysByPattern(server, db, pattern)
+                const { success, msg, data } = await DeleteKeysByPattern(server, db, pattern, false, serialNo)
                 if (success) {
                     canceled = get(data, 'canceled', false)
                     deleted = get(data, 'deleted', [])
@@ -1965,32 +1973,54 @@ const useBrowserStore = defineStore('browser', {
          * @param {string} server
          * @param {number} db
          * @param {boolean} async
+         * @param {string} confirmToken should be the same as server name
          * @return {Promise<boolean>}
          */
-        async flushDatabase(server, db, async) {
-            try {
-                const { success = false } = await FlushDB(server, db, async)
+        async flushDatabase(server, db, async, confirmToken) {
+            const { success = false, msg } = await FlushDB(server, db, async, confirmToken)
+            if (success !== true) {
+                throw new Error(msg)
+            }
+            this._clearFlushedKeys(server)
+            return true
+        },
 
-                if (success === true) {
-                    /** @type RedisServerState **/
-                    const serverInst = this.servers[server]
-                    if (serverInst != null) {
-                        // update tree view data
-                        serverInst.removeKeyNode()
-                    }
-                    // set tab content empty
-                    const tab = useTabStore()
-                    tab.emptyTab(server)
-                    tab.setSelectedKeys(server)
-                    tab.setCheckedKeys(server)
-                    tab.setExpandedKeys(server)
-                    return true
-                }
-            } finally {
+        /**
+         * flush all databases
+         * @param {string} server
+         * @param {boolean} async
+         * @param {string} confirmToken should be the same as server name
+         * @return {Promise<boolean>}
+         */
+        async flushAllDatabases(server, async, confirmToken) {
+            const { success = false, msg } = await FlushAll(server, async, confirmToken)
+            if (success !== true) {
+                throw new Error(msg)
             }
+            this._clearFlushedKeys(server)
             return true
         },
 
+        /**
+         * remove key nodes and tab content after flushed
+         * @param {string} server
+         * @private
+         */
+        _clearFlushedKeys(server) {
+            /** @type RedisServerState **/
+            const serverInst = this.servers[server]
+            if (serverInst != null) {
+                // update tree view data
+                serverInst.removeKeyNode()
+            }
+            // set tab content empty
+            const tab = useTabStore()
+            tab.emptyTab(server)
+            tab.setSelectedKeys(server)
+            tab.setCheckedKeys(server)
+            tab.setExpandedKeys(server)
+        },
+
         /**
          * rename key
          * @param {string} server
@@ -2000,7 +2030,7 @@ const useBrowserStore = defineStore('browser', {
          * @returns {Promise<{[msg]: string, success: boolean, [nodeKey]: string}>}
          */
         async renameKey(server, db, key, newKey) {
-            const { success = false, msg } = await RenameKey(server, db, key, newKey)
+            const { success = false, msg } = await RenameKey(server, db, key, newKey, false)
             if (success) {
                 // delete old key and add new key struct
                 /** @type RedisServerState **/
@@ -8,7 +8,7 @@ import {
     GetConnection,
     ImportConnections,
     ListConnection,
-    ParseConnectURL,
+    ParseURL,
     RenameGroup,
     SaveConnection,
     SaveLastDB,
@@ -345,11 +345,11 @@ const useConnectionStore = defineStore('connections', {
         /**
          * delete group by name
          * @param {string} name
-         * @param {boolean} [includeConn]
+         * @param {boolean} [cascade] remove all connections under the group
          * @returns {Promise<{success: boolean, [msg]: string}>}
          */
-        async deleteGroup(name, includeConn) {
-            const { success, msg } = await DeleteGroup(name, includeConn === true)
+        async deleteGroup(name, cascade) {
+            const { success, msg } = await DeleteGroup(name, cascade === true)
             if (!success) {
                 return { success: false, msg }
             }
@@ -463,7 +463,7 @@ const useConnectionStore = defineStore('connections', {
                 throw new Error('no text in clipboard')
             }
 
-            const { success, msg, data } = await ParseConnectURL(urlString)
+            const { success, msg, data } = await ParseURL(urlString)
             if (!success || !isObject(data)) {
                 throw new Error(msg || 'unknown')
             }
@@ -49,6 +49,7 @@ const usePreferencesStore = defineStore('preferences', {
             fontFamily: [],
             fontSize: 14,
             scanSize: 3000,
+            scanAutoTune: false,
             keyIconStyle: 0,
             useSysProxy: false,
             useSysProxyHttp: false,
@@ -11,3 +11,12 @@ export const isRedisGlob = (str) => {
     }
     return false
 }
+
+/**
+ * escape glob characters of literal key name, to match it exactly in SCAN
+ * @param {string} str
+ * @return {string}
+ */
+export const escapeRedisGlob = (str) => {
+    return isEmpty(str) ? str : str.replace(/[\\*?[\]]/g, '\\$&')
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/options/windows"
 	runtime2 "github.com/wailsapp/wails/v2/pkg/runtime"
 	"runtime"
+	"time"
 	"tinyrdm/backend/consts"
 	"tinyrdm/backend/services"
 )
@@ -36,6 +37,12 @@ func main() {
 	cliSvc := services.Cli()
 	monitorSvc := services.Monitor()
 	pubsubSvc := services.Pubsub()
+	serverSvc := services.Server()
+	aclSvc := services.Acl()
+	clusterSvc := services.Cluster()
+	scriptSvc := services.Script()
+	auditSvc := services.Audit()
+	streamSvc := services.Stream()
 	prefSvc := services.Preferences()
 	prefSvc.SetAppVersion(version)
 	prefSvc.UpdateEnv()
@@ -77,6 +84,12 @@ func main() {
 			cliSvc.Start(ctx)
 			monitorSvc.Start(ctx)
 			pubsubSvc.Start(ctx)
+			serverSvc.Start(ctx)
+			aclSvc.Start(ctx)
+			clusterSvc.Start(ctx)
+			scriptSvc.Start(ctx)
+			auditSvc.Start(ctx)
+			streamSvc.Start(ctx)
 
 			services.GA().SetSecretKey(gaMeasurementID, gaSecretKey)
 			services.GA().Startup(version)
@@ -95,7 +108,8 @@ func main() {
 			browserSvc.Stop()
 			cliSvc.CloseAll()
 			monitorSvc.StopAll()
-			pubsubSvc.StopAll()
+			pubsubSvc.Shutdown(consts.DEFAULT_SHUTDOWN_TIMEOUT * time.Millisecond)
+			serverSvc.StopAll()
 		},
 		Bind: []interface{}{
 			sysSvc,
@@ -104,6 +118,12 @@ func main() {
 			cliSvc,
 			monitorSvc,
 			pubsubSvc,
+			serverSvc,
+			aclSvc,
+			clusterSvc,
+			scriptSvc,
+			auditSvc,
+			streamSvc,
 			prefSvc,
 		},
 		Mac: &mac.Options{